@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/mmorhun/konflux-task-cli/cmd/git"
+)
+
+// gitCmd represents the git command
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "A subcommand group to work with git repositories",
+}
+
+func init() {
+	rootCmd.AddCommand(gitCmd)
+
+	gitCmd.AddCommand(git.MirrorUpdateCmd)
+}
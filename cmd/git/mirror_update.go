@@ -0,0 +1,35 @@
+package git
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/mmorhun/konflux-task-cli/pkg/commands"
+	"github.com/mmorhun/konflux-task-cli/pkg/common"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+// MirrorUpdateCmd represents the mirror-update command
+var MirrorUpdateCmd = &cobra.Command{
+	Use:   "mirror-update",
+	Short: "Creates or refreshes a bare mirror of a git repository",
+	Long: `Creates a bare mirror of the given git repository in "mirror-dir" or fetches all refs into the existing one.
+If the directory contains anything else, e.g. a mirror of another repository, it's recreated.
+
+The mirror is intended to be kept on a node-local cache volume
+and used as "reference-dir" of gitclone command to speed up clones.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		l.Logger.Info("Starting git mirror update")
+		gitMirrorUpdate, err := commands.NewGitMirrorUpdate(cmd)
+		if err != nil {
+			l.Logger.Fatal(err)
+		}
+		if err := gitMirrorUpdate.Run(); err != nil {
+			l.Logger.Fatal(err)
+		}
+		l.Logger.Info("Finishing git mirror update")
+	},
+}
+
+func init() {
+	common.RegisterParameters(MirrorUpdateCmd, commands.GitMirrorUpdateParamsConfig)
+}
@@ -12,11 +12,70 @@ var gitcloneCmd = &cobra.Command{
 	Use:   "gitclone",
 	Short: "Clones a git repository",
 	Long: `A Konflux helper command to clone git repository.
-Mandatory parameters are "url" and "branch".
+Mandatory parameter is "url". If "branch" is not set, the default branch of the remote repository
+is detected and cloned. The cloned branch is written into RESULT_BRANCH result.
 Note, parameters could be passed as flag or via environmebt variables.
 Flags take precedence over environment variable.
 
-The command requires git cli installed.`,
+Supported protocols are https and ssh (including scp-like git@host:org/repo form).
+For ssh, private keys, known_hosts and config files are taken from "ssh-directory",
+which has the layout of Tekton ssh-directory workspace.
+Credentials embedded into the url are rejected, use credentials parameters instead.
+
+Allowed repositories could be restricted with "url-policy-file", a JSON file in the form of
+{"allow": ["github.com/org", "*.example.com"], "deny": ["github.com/org/repo"]}.
+Entries are matched against the normalized host and path prefix of the url and submodule urls.
+Deny entries take precedence, a non-empty allow list must contain a matching entry.
+
+To checkout a specific commit, tag or ref (e.g. refs/pull/123/head) use "revision" parameter.
+In such case only the requested revision is fetched, which works with shallow "depth" as well.
+
+Submodules are checked out if "submodules" is set to "top-level" or "recursive".
+The list of checked out submodules is written into optional RESULT_SUBMODULES result as JSON.
+
+The repository is cloned into "output-dir", derived from the url if not set.
+With "delete-existing" the output directory content is deleted before clone.
+With "update-existing" an existing clone of the same repository in the output directory
+is fetched and reset to the requested revision, otherwise it's cloned from scratch.
+
+To speed up clone, objects could be borrowed from a local repository given by "reference-dir",
+e.g. a mirror maintained by "git mirror-update" command. Set "dissociate" to copy the borrowed objects.
+
+If "base-ref" is set, files changed since the checked out commit diverged from the base ref
+are written into RESULT_CHANGED_FILES result as JSON array. Shallow history is deepened as needed.
+RESULT_WATCHED_PATHS_CHANGED tells whether any of "watch-paths" glob patterns matches a changed file.
+
+With "verify-signature" the checked out commit must be signed by a key from "gpg-keyring"
+or "ssh-allowed-signers", the signer is written into RESULT_COMMIT_SIGNER result.
+GPG signature verification requires gpg installed.
+
+Git LFS objects are fetched if "lfs" is set, which requires git-lfs installed.
+
+Additional repositories could be cloned next to the main one from "manifest", a YAML or JSON list like:
+- url: https://github.com/org/config
+  revision: v1.0.0    # optional, remote HEAD if not set
+  path: deps/config   # optional, derived from the url if not set
+  depth: 1            # optional
+Up to "manifest-parallelism" repositories are cloned at once. Their paths and checked out commits
+are written into RESULT_MANIFEST result as JSON, other results describe the main repository.
+
+To use the sources in steps running as a different user, set "chown" to numeric uid[:gid]
+and/or "chmod-mode" to octal permissions, e.g. 0775, which are applied to all cloned files.
+The source directory is trusted via git "safe.directory" option for git commands of this command only.
+The absolute path of the source directory is written into RESULT_ABSOLUTE_SOURCE_DIR result.
+
+A copy of the checked out sources is written into "archive-path" if "archive-format" is set:
+"tar.gz" archives the working tree, including submodules, but without git metadata,
+"git-bundle" contains the checked out commit with its fetched history.
+The archives are reproducible: tar entries are sorted and have the commit date as modification time.
+The archive path and its sha256 digest are written into RESULT_ARCHIVE_PATH and RESULT_ARCHIVE_SHA256 results.
+
+Behind a proxy, set "http-proxy", "https-proxy" and "no-proxy". With TLS interception, "ca-bundle"
+must point to a PEM file with all trusted certificate authorities, it replaces the system ones.
+The settings are applied to git commands only, not to the whole process.
+
+The command uses git cli if it's installed, otherwise falls back to go-git backend,
+which supports basic clone only and no proxy settings. Use "git-backend" to select the backend explicitly.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		l.Logger.Info("Starting git clone")
 		gitClone, err := commands.NewGitClone(cmd)
@@ -36,7 +95,7 @@ func init() {
 	common.RegisterParameters(gitcloneCmd, commands.GitCloneParamsConfig)
 	// The above could be done manually:
 	// gitcloneCmd.Flags().String("url", "", "Git URL to clone from")
-	// gitcloneCmd.Flags().String("branch", "main", "Branch to clone from")
+	// gitcloneCmd.Flags().String("branch", "", "Branch to clone from")
 	// gitcloneCmd.Flags().IntP("depth", "d", 0, "Clone depth")
 	// gitcloneCmd.Flags().Bool("verbose", false, "Activates verbose mode")
 }
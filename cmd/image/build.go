@@ -12,6 +12,25 @@ import (
 var BuildCmd = &cobra.Command{
 	Use:   "build",
 	Short: "Build a container image",
+	Long: `Builds a container image from the source directory with buildah and pushes it.
+
+Build arguments are read from "build-args-file", KEY=VALUE per line, and "build-args" parameter,
+which overrides the file values. Well-known COMMIT_SHA and SOURCE_URL build arguments are set
+from "commit-sha" and "source-url" parameters, unless they are set explicitly.
+
+With "platforms", e.g. linux/amd64,linux/arm64, the image is built for each platform,
+using qemu emulation for foreign architectures if it's registered on the host.
+The images are pushed within an OCI image index, which digest is written into RESULT_IMAGE_DIGEST.
+Digests of the platform images are written into RESULT_PLATFORM_DIGESTS result as JSON map.
+
+Secrets for RUN --mount=type=secret,id=<id> instructions are taken from "secrets-dir",
+e.g. a mounted Kubernetes secret, where each file is a secret with the file name as id,
+and from "secrets" parameter in id=path form. Secret values are masked in the logs.
+
+With "hermetic" RUN instructions have no network access. Prefetched dependencies from "prefetch-dir"
+are mounted into the build at /cachi2. If the directory contains cachi2.env file, it's sourced
+at the beginning of each RUN instruction of a generated copy of the Dockerfile.
+Whether the build was hermetic is written into RESULT_HERMETIC result.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		l.Logger.Info("Starting image build")
 		imageBuild, err := commands.NewImageBuild(cmd)
@@ -0,0 +1,56 @@
+package integration_tests
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGitMirrorUpdate(t *testing.T) {
+	RegisterFailHandler(func(message string, callerSkip ...int) {
+		fmt.Printf("Test Failure: %s\n", message)
+		t.FailNow() // Terminate the test immediately
+	})
+	ExpectKonfluxCliCompiled()
+
+	volumeDir := CreateTempDir("git-mirror-pvc-*")
+	defer os.RemoveAll(volumeDir)
+
+	const repoUrl = "https://github.com/devfile-samples/devfile-sample-go-basic"
+	// Local bare repository is a stand-in for the remote
+	const remoteDir = "/pvc/remote.git"
+	const mirrorDir = "/pvc/mirror.git"
+
+	container := NewTestContainer("git-mirror-update", GitCloneImage, true)
+	container.AddVolume(volumeDir, "/pvc")
+	container.SetWorkdir("/pvc")
+	err := container.Start()
+	Expect(err).ToNot(HaveOccurred())
+	defer container.Delete()
+
+	err = container.CopyFileIntoContainer("../"+KonfluxCli, "/usr/bin/")
+	Expect(err).ToNot(HaveOccurred())
+
+	err = container.ExecuteAndWait("git", "clone", "--bare", repoUrl, remoteDir)
+	Expect(err).ToNot(HaveOccurred())
+
+	// Create the mirror
+	err = container.ExecuteAndWait(KonfluxCli, "git", "mirror-update", "--url", "file://"+remoteDir, "--mirror-dir", mirrorDir)
+	Expect(err).ToNot(HaveOccurred())
+	mirrorConfig, err := container.GetFileContent(mirrorDir + "/config")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(mirrorConfig).To(ContainSubstring("mirror = true"))
+
+	// Refresh the mirror
+	err = container.ExecuteAndWait(KonfluxCli, "git", "mirror-update", "--url", "file://"+remoteDir, "--mirror-dir", mirrorDir)
+	Expect(err).ToNot(HaveOccurred())
+
+	// Clone borrowing objects from the mirror
+	err = container.ExecuteAndWait(KonfluxCli, "gitclone", "--url", repoUrl, "--branch", "main", "--reference-dir", mirrorDir)
+	Expect(err).ToNot(HaveOccurred())
+	alternates, err := container.GetFileContent("/pvc/devfile-sample-go-basic/.git/objects/info/alternates")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(alternates).To(ContainSubstring(mirrorDir + "/objects"))
+}
@@ -1,10 +1,10 @@
 package cliwrappers
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
-	"strings"
 
 	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
 )
@@ -12,6 +12,10 @@ import (
 type BuildahCliInterface interface {
 	Build(args *BuildahBuildArgs) (string, string, error)
 	Push(image string) (string, error)
+	ManifestCreate(manifest string) error
+	ManifestAdd(manifest, image string) error
+	ManifestPush(manifest string) (string, error)
+	ManifestInspect(manifest string) (*BuildahManifestList, error)
 }
 
 var _ BuildahCliInterface = &BuildahCli{}
@@ -50,6 +54,22 @@ type BuildahBuildArgs struct {
 	SourceDir      string
 	Annotations    []string
 	Labels         []string
+	// BuildArgs in KEY=VALUE form
+	BuildArgs []string
+	// Platform to build for in os/arch[/variant] form, the host platform if not set
+	Platform string
+	// Secrets available to RUN --mount=type=secret instructions
+	Secrets []BuildahSecret
+	// Network mode of RUN instructions, e.g. none, the default one if not set
+	Network string
+	// Volumes to mount into RUN instructions in src:dst[:options] form
+	Volumes []string
+}
+
+type BuildahSecret struct {
+	Id string
+	// Src is path to the file with the secret value
+	Src string
 }
 
 // Build builds the image and returns the built image and its digest
@@ -58,25 +78,41 @@ func (b *BuildahCli) Build(args *BuildahBuildArgs) (string, string, error) {
 		return "", "", errors.New("image to build must be set")
 	}
 
-	buildahArgs := []string{"build", "--no-cache", "--ulimit", "nofile=4096:4096", "--http-proxy=false"}
+	// Arguments are passed to buildah as is, without shell, so values may contain any characters.
+	// Values are attached to their options, so values starting with dash are not taken as options.
+	buildahArgs := []string{"build", "--no-cache", "--ulimit=nofile=4096:4096", "--http-proxy=false"}
 	if args.DockerfilePath != "" {
-		buildahArgs = append(buildahArgs, "-f", args.DockerfilePath)
+		buildahArgs = append(buildahArgs, "--file="+args.DockerfilePath)
+	}
+	if args.Platform != "" {
+		buildahArgs = append(buildahArgs, "--platform="+args.Platform)
 	}
 	for _, label := range args.Labels {
-		buildahArgs = append(buildahArgs, "--label", label)
+		buildahArgs = append(buildahArgs, "--label="+label)
 	}
 	for _, annotation := range args.Annotations {
-		buildahArgs = append(buildahArgs, "--annotation", annotation)
+		buildahArgs = append(buildahArgs, "--annotation="+annotation)
 	}
-	buildahArgs = append(buildahArgs, "-t", args.Image, ".")
-
-	buildahCmd := "buildah " + strings.Join(buildahArgs, " ")
+	for _, buildArg := range args.BuildArgs {
+		buildahArgs = append(buildahArgs, "--build-arg="+buildArg)
+	}
+	for _, secret := range args.Secrets {
+		buildahArgs = append(buildahArgs, "--secret=id="+secret.Id+",src="+secret.Src)
+	}
+	if args.Network != "" {
+		buildahArgs = append(buildahArgs, "--network="+args.Network)
+	}
+	for _, volume := range args.Volumes {
+		buildahArgs = append(buildahArgs, "--volume="+volume)
+	}
+	buildahArgs = append(buildahArgs, "--tag="+args.Image, ".")
 
 	unshareArgs := []string{"-Uf", "--keep-caps", "-r", "--map-users", "1,1,65536", "--map-groups", "1,1,65536", "--mount"}
 	if args.SourceDir != "" {
-		unshareArgs = append(unshareArgs, "-w", args.SourceDir)
+		unshareArgs = append(unshareArgs, "--wd="+args.SourceDir)
 	}
-	unshareArgs = append(unshareArgs, "--", "sh", "-c", buildahCmd)
+	unshareArgs = append(unshareArgs, "--", "buildah")
+	unshareArgs = append(unshareArgs, buildahArgs...)
 
 	stdout, stderr, _, err := b.Executor.Execute("unshare", unshareArgs...)
 	if err != nil {
@@ -140,3 +176,83 @@ func (b *BuildahCli) Push(image string) (string, error) {
 
 	return stdout, nil
 }
+
+// BuildahManifestList is a subset of image index as printed by buildah manifest inspect.
+type BuildahManifestList struct {
+	Manifests []BuildahManifestDescriptor `json:"manifests"`
+}
+
+type BuildahManifestDescriptor struct {
+	Digest   string                  `json:"digest"`
+	Platform BuildahManifestPlatform `json:"platform"`
+}
+
+type BuildahManifestPlatform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// ManifestCreate creates a new local manifest list with the given name
+func (b *BuildahCli) ManifestCreate(manifest string) error {
+	stdout, stderr, _, err := b.Executor.Execute("buildah", "manifest", "create", manifest)
+	if err != nil {
+		l.Logger.Errorf("[stdout]:\n%s", stdout)
+		l.Logger.Errorf("[stderr]:\n%s", stderr)
+		return fmt.Errorf("buildah manifest create failed: %v", err)
+	}
+	return nil
+}
+
+// ManifestAdd adds the local image into the manifest list
+func (b *BuildahCli) ManifestAdd(manifest, image string) error {
+	stdout, stderr, _, err := b.Executor.Execute("buildah", "manifest", "add", manifest, "containers-storage:"+image)
+	if err != nil {
+		l.Logger.Errorf("[stdout]:\n%s", stdout)
+		l.Logger.Errorf("[stderr]:\n%s", stderr)
+		return fmt.Errorf("buildah manifest add failed: %v", err)
+	}
+	return nil
+}
+
+// ManifestPush pushes the manifest list together with all its images to the registry
+// as the image with the same name and returns the manifest list digest
+func (b *BuildahCli) ManifestPush(manifest string) (string, error) {
+	const digestFile = "/tmp/manifest-digestfile"
+	stdout, stderr, _, err := b.Executor.Execute("buildah", "manifest", "push", "--all", "--digestfile", digestFile, manifest, "docker://"+manifest)
+	if err != nil {
+		l.Logger.Errorf("[stdout]:\n%s", stdout)
+		l.Logger.Errorf("[stderr]:\n%s", stderr)
+		return "", fmt.Errorf("buildah manifest push failed: %v", err)
+	}
+
+	if b.Verbose {
+		l.Logger.Info("[stdout]:\n" + stdout)
+	}
+
+	stdout, stderr, _, err = b.Executor.Execute("cat", digestFile)
+	if err != nil {
+		l.Logger.Errorf("[stdout]:\n%s", stdout)
+		l.Logger.Errorf("[stderr]:\n%s", stderr)
+		return "", fmt.Errorf("failed to read digest file: %v", err)
+	}
+
+	return stdout, nil
+}
+
+// ManifestInspect returns the images of the manifest list.
+// The manifest could be a local manifest list or a remote image index, e.g. docker://registry/org/app@sha256:...
+func (b *BuildahCli) ManifestInspect(manifest string) (*BuildahManifestList, error) {
+	stdout, stderr, _, err := b.Executor.Execute("buildah", "manifest", "inspect", manifest)
+	if err != nil {
+		l.Logger.Errorf("[stdout]:\n%s", stdout)
+		l.Logger.Errorf("[stderr]:\n%s", stderr)
+		return nil, fmt.Errorf("buildah manifest inspect failed: %v", err)
+	}
+
+	manifestList := &BuildahManifestList{}
+	if err := json.Unmarshal([]byte(stdout), manifestList); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest list: %w", err)
+	}
+	return manifestList, nil
+}
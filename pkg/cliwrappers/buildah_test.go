@@ -0,0 +1,218 @@
+package cliwrappers_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+)
+
+const (
+	buildahTestImage  = "quay.io/org/app:tag"
+	buildahTestDigest = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+)
+
+// Values which break or get interpreted when passed through shell.
+var hostileValues = []string{
+	"description=My app's image",
+	`summary="quoted" value`,
+	"cmd=$(touch /tmp/pwned)",
+	"cmd2=`touch /tmp/pwned`",
+	"var=$HOME ${PATH}",
+	"multi=line1\nline2",
+	"glob=*; rm -rf / | cat & echo",
+	"--dash=option-like",
+	`backslash=a\b\\c`,
+}
+
+func setupBuildahCli() (*cliwrappers.BuildahCli, *mockExecutor) {
+	executor := &mockExecutor{}
+	buildahCli := &cliwrappers.BuildahCli{
+		Executor: executor,
+		Verbose:  false,
+	}
+	return buildahCli, executor
+}
+
+func TestBuildahCli_Build(t *testing.T) {
+	g := NewWithT(t)
+	buildahCli, executor := setupBuildahCli()
+
+	executor.executeFunc = func(command string, args ...string) (string, string, int, error) {
+		g.Expect(command).To(Equal("unshare"))
+		g.Expect(args).To(Equal([]string{
+			"-Uf", "--keep-caps", "-r", "--map-users", "1,1,65536", "--map-groups", "1,1,65536", "--mount",
+			"--wd=source", "--",
+			"buildah", "build", "--no-cache", "--ulimit=nofile=4096:4096", "--http-proxy=false",
+			"--file=Containerfile",
+			"--label=name=app",
+			"--annotation=org.opencontainers.image.title=app",
+			"--build-arg=VERSION=1.0",
+			"--secret=id=token,src=/secrets/token",
+			"--network=none",
+			"--volume=/prefetch:/cachi2",
+			"--tag=" + buildahTestImage, ".",
+		}))
+		return "Successfully tagged " + buildahTestImage + "\n" + buildahTestDigest + "\n", "", 0, nil
+	}
+
+	image, digest, err := buildahCli.Build(&cliwrappers.BuildahBuildArgs{
+		Image:          buildahTestImage,
+		DockerfilePath: "Containerfile",
+		SourceDir:      "source",
+		Labels:         []string{"name=app"},
+		Annotations:    []string{"org.opencontainers.image.title=app"},
+		BuildArgs:      []string{"VERSION=1.0"},
+		Secrets:        []cliwrappers.BuildahSecret{{Id: "token", Src: "/secrets/token"}},
+		Network:        "none",
+		Volumes:        []string{"/prefetch:/cachi2"},
+	})
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(image).To(Equal(buildahTestImage))
+	g.Expect(digest).To(Equal("sha256:" + buildahTestDigest))
+}
+
+func TestBuildahCli_Build_HostileValuesPassedVerbatim(t *testing.T) {
+	g := NewWithT(t)
+	buildahCli, executor := setupBuildahCli()
+
+	var buildahArgs []string
+	executor.executeFunc = func(command string, args ...string) (string, string, int, error) {
+		g.Expect(command).To(Equal("unshare"))
+		g.Expect(args).NotTo(ContainElement("sh"))
+		g.Expect(args).NotTo(ContainElement("-c"))
+		buildahArgs = args
+		return "Successfully tagged " + buildahTestImage + "\n" + buildahTestDigest + "\n", "", 0, nil
+	}
+
+	_, _, err := buildahCli.Build(&cliwrappers.BuildahBuildArgs{
+		Image:       buildahTestImage,
+		SourceDir:   "source dir with 'quotes'",
+		Labels:      hostileValues,
+		Annotations: hostileValues,
+		BuildArgs:   []string{"ARG=$(id) 'x' \"y\""},
+	})
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(buildahArgs).To(ContainElement("--wd=source dir with 'quotes'"))
+	for _, value := range hostileValues {
+		g.Expect(buildahArgs).To(ContainElement("--label=" + value))
+		g.Expect(buildahArgs).To(ContainElement("--annotation=" + value))
+	}
+	g.Expect(buildahArgs).To(ContainElement("--build-arg=ARG=$(id) 'x' \"y\""))
+}
+
+func TestBuildahCli_Build_FailsOnEmptyImage(t *testing.T) {
+	g := NewWithT(t)
+	buildahCli, _ := setupBuildahCli()
+
+	_, _, err := buildahCli.Build(&cliwrappers.BuildahBuildArgs{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("image to build must be set"))
+}
+
+// Runs the build with real executor and fake unshare, which records its arguments,
+// to make sure no shell is involved between the executor and the executed command.
+func TestBuildahCli_Build_HostileValuesReachCommandUnchanged(t *testing.T) {
+	g := NewWithT(t)
+
+	binDir := t.TempDir()
+	argsFile := filepath.Join(t.TempDir(), "args")
+	fakeUnshare := "#!/bin/sh\n" +
+		"printf '%s\\0' \"$@\" > \"$FAKE_UNSHARE_ARGS_FILE\"\n" +
+		"echo \"Successfully tagged " + buildahTestImage + "\"\n" +
+		"echo " + buildahTestDigest + "\n"
+	g.Expect(os.WriteFile(filepath.Join(binDir, "unshare"), []byte(fakeUnshare), 0755)).To(Succeed())
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("FAKE_UNSHARE_ARGS_FILE", argsFile)
+
+	buildahCli := &cliwrappers.BuildahCli{Executor: cliwrappers.NewCliExecutor(false)}
+	_, _, err := buildahCli.Build(&cliwrappers.BuildahBuildArgs{
+		Image:  buildahTestImage,
+		Labels: hostileValues,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	recordedArgs, err := os.ReadFile(argsFile)
+	g.Expect(err).NotTo(HaveOccurred())
+	args := strings.Split(strings.TrimSuffix(string(recordedArgs), "\x00"), "\x00")
+	for _, value := range hostileValues {
+		g.Expect(args).To(ContainElement("--label=" + value))
+	}
+}
+
+func TestBuildahCli_Build_Platform(t *testing.T) {
+	g := NewWithT(t)
+	buildahCli, executor := setupBuildahCli()
+
+	executor.executeFunc = func(command string, args ...string) (string, string, int, error) {
+		g.Expect(args).To(ContainElement("--platform=linux/arm64"))
+		return "Successfully tagged " + buildahTestImage + "\n" + buildahTestDigest + "\n", "", 0, nil
+	}
+
+	_, _, err := buildahCli.Build(&cliwrappers.BuildahBuildArgs{Image: buildahTestImage, Platform: "linux/arm64"})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestBuildahCli_Manifest(t *testing.T) {
+	g := NewWithT(t)
+	buildahCli, executor := setupBuildahCli()
+
+	var commands [][]string
+	executor.executeFunc = func(command string, args ...string) (string, string, int, error) {
+		commands = append(commands, append([]string{command}, args...))
+		switch {
+		case command == "cat":
+			return "sha256:" + buildahTestDigest, "", 0, nil
+		case len(args) > 1 && args[1] == "inspect":
+			return `{
+				"schemaVersion": 2,
+				"mediaType": "application/vnd.oci.image.index.v1+json",
+				"manifests": [
+					{"digest": "sha256:aaa", "platform": {"architecture": "amd64", "os": "linux"}},
+					{"digest": "sha256:bbb", "platform": {"architecture": "arm", "os": "linux", "variant": "v7"}}
+				]
+			}`, "", 0, nil
+		}
+		return "", "", 0, nil
+	}
+
+	g.Expect(buildahCli.ManifestCreate(buildahTestImage)).To(Succeed())
+	g.Expect(buildahCli.ManifestAdd(buildahTestImage, buildahTestImage+"-linux-amd64")).To(Succeed())
+	digest, err := buildahCli.ManifestPush(buildahTestImage)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(digest).To(Equal("sha256:" + buildahTestDigest))
+	manifestList, err := buildahCli.ManifestInspect("docker://" + buildahTestImage + "@sha256:" + buildahTestDigest)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(commands).To(Equal([][]string{
+		{"buildah", "manifest", "create", buildahTestImage},
+		{"buildah", "manifest", "add", buildahTestImage, "containers-storage:" + buildahTestImage + "-linux-amd64"},
+		{"buildah", "manifest", "push", "--all", "--digestfile", "/tmp/manifest-digestfile", buildahTestImage, "docker://" + buildahTestImage},
+		{"cat", "/tmp/manifest-digestfile"},
+		{"buildah", "manifest", "inspect", "docker://" + buildahTestImage + "@sha256:" + buildahTestDigest},
+	}))
+	g.Expect(manifestList.Manifests).To(Equal([]cliwrappers.BuildahManifestDescriptor{
+		{Digest: "sha256:aaa", Platform: cliwrappers.BuildahManifestPlatform{Architecture: "amd64", OS: "linux"}},
+		{Digest: "sha256:bbb", Platform: cliwrappers.BuildahManifestPlatform{Architecture: "arm", OS: "linux", Variant: "v7"}},
+	}))
+}
+
+func TestBuildahCli_ManifestPush_FailsOnError(t *testing.T) {
+	g := NewWithT(t)
+	buildahCli, executor := setupBuildahCli()
+
+	executor.executeFunc = func(command string, args ...string) (string, string, int, error) {
+		return "", "unauthorized", 1, errors.New("exit status 1")
+	}
+
+	_, err := buildahCli.ManifestPush(buildahTestImage)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("buildah manifest push failed"))
+}
@@ -12,24 +12,49 @@ import (
 	"syscall"
 
 	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+	"github.com/mmorhun/konflux-task-cli/pkg/redact"
 )
 
 type CliExecutorInterface interface {
 	Execute(command string, args ...string) (stdout, stderr string, exitCode int, err error)
 	ExecuteInDir(wordir, command string, args ...string) (stdout, stderr string, exitCode int, err error)
 	ExecuteWithOutput(command string, args ...string) (stdout, stderr string, exitCode int, err error)
+	WithEnv(env []string) CliExecutorInterface
 }
 
 var _ CliExecutorInterface = &CliExecutor{}
 
 type CliExecutor struct {
 	Verbose bool
+	// Env holds additional environment variables in "NAME=value" form.
+	// They are set only for the executed commands, the CLI process environment is not modified.
+	Env []string
 }
 
 func NewCliExecutor(verbose bool) *CliExecutor {
 	return &CliExecutor{Verbose: verbose}
 }
 
+// WithEnv returns a copy of the executor that adds given environment variables to executed commands.
+// Each entry must be in "NAME=value" form.
+func (e *CliExecutor) WithEnv(env []string) CliExecutorInterface {
+	newEnv := make([]string, 0, len(e.Env)+len(env))
+	newEnv = append(newEnv, e.Env...)
+	newEnv = append(newEnv, env...)
+	return &CliExecutor{
+		Verbose: e.Verbose,
+		Env:     newEnv,
+	}
+}
+
+func (e *CliExecutor) newCommand(command string, args ...string) *exec.Cmd {
+	cmd := exec.Command(command, args...)
+	if len(e.Env) > 0 {
+		cmd.Env = append(os.Environ(), e.Env...)
+	}
+	return cmd
+}
+
 // Execute runs specified command with given arguments.
 // Returns stdout, stderr, exit code, error
 func (e *CliExecutor) Execute(command string, args ...string) (string, string, int, error) {
@@ -39,7 +64,7 @@ func (e *CliExecutor) Execute(command string, args ...string) (string, string, i
 // ExecuteInDir runs given command in the specified directory.
 // Returns stdout, stderr, exit code, error
 func (e *CliExecutor) ExecuteInDir(wordir, command string, args ...string) (string, string, int, error) {
-	cmd := exec.Command(command, args...)
+	cmd := e.newCommand(command, args...)
 	if wordir != "" {
 		cmd.Dir = wordir
 	}
@@ -49,7 +74,7 @@ func (e *CliExecutor) ExecuteInDir(wordir, command string, args ...string) (stri
 	cmd.Stderr = &stderrBuf
 
 	if e.Verbose {
-		l.Logger.Infof("Executing command: %s %s", command, strings.Join(args, " "))
+		l.Logger.Infof("Executing command: %s %s", command, strings.Join(redact.Strings(args), " "))
 	}
 
 	err := cmd.Run()
@@ -60,7 +85,7 @@ func (e *CliExecutor) ExecuteInDir(wordir, command string, args ...string) (stri
 // ExecuteWithOutput runs a command with args while printing stdout and stderr in real time.
 // Returns stdout, stderr, exit code, error
 func (e *CliExecutor) ExecuteWithOutput(command string, args ...string) (string, string, int, error) {
-	cmd := exec.Command(command, args...)
+	cmd := e.newCommand(command, args...)
 
 	stdoutPipe, err := cmd.StdoutPipe()
 	if err != nil {
@@ -72,7 +97,7 @@ func (e *CliExecutor) ExecuteWithOutput(command string, args ...string) (string,
 	}
 
 	if e.Verbose {
-		l.Logger.Infof("Executing command: %s %s", command, strings.Join(args, " "))
+		l.Logger.Infof("Executing command: %s %s", command, strings.Join(redact.Strings(args), " "))
 	}
 
 	if err := cmd.Start(); err != nil {
@@ -85,7 +110,7 @@ func (e *CliExecutor) ExecuteWithOutput(command string, args ...string) (string,
 		scanner := bufio.NewScanner(r)
 		for scanner.Scan() {
 			line := scanner.Text()
-			fmt.Fprintln(w, line)
+			fmt.Fprintln(w, redact.String(line))
 			buf.WriteString(line + "\n")
 		}
 	}
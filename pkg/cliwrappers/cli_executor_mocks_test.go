@@ -10,6 +10,9 @@ type mockExecutor struct {
 	executeFunc       func(command string, args ...string) (string, string, int, error)
 	executeInDirFunc  func(workdir, command string, args ...string) (string, string, int, error)
 	executeWithOutput func(command string, args ...string) (string, string, int, error)
+
+	// env accumulates environment passed via WithEnv
+	env []string
 }
 
 func (m *mockExecutor) Execute(command string, args ...string) (string, string, int, error) {
@@ -32,3 +35,8 @@ func (m *mockExecutor) ExecuteWithOutput(command string, args ...string) (stdout
 	}
 	return "", "", 0, nil
 }
+
+func (m *mockExecutor) WithEnv(env []string) cliwrappers.CliExecutorInterface {
+	m.env = append(m.env, env...)
+	return m
+}
@@ -7,20 +7,55 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
 )
 
 type GitCliInterface interface {
-	Clone(url, branch string, depth int) (string, error)
+	Clone(args *GitCloneArgs) (string, error)
 	GetRepoHeadFullSha(gitRepoDir string) (string, error)
+	Init(repoDir string) error
+	AddRemote(repoDir, name, url string) error
+	Fetch(args *GitFetchArgs) error
+	Checkout(repoDir, ref string) error
+	GetRemoteUrl(repoDir, remote string) (string, error)
+	GetRemoteDefaultBranch(url string) (string, error)
+	ResetHard(repoDir, ref string) error
+	Clean(repoDir string) error
+	Repack(repoDir string) error
+	IsBareRepository(repoDir string) (bool, error)
+	IsShallowRepository(repoDir string) (bool, error)
+	MergeBase(repoDir, ref1, ref2 string) (string, error)
+	DiffNames(repoDir, fromRef, toRef string) ([]string, error)
+	SparseCheckoutSet(repoDir string, paths []string) error
+	Merge(args *GitMergeArgs) error
+	SubmoduleUpdate(args *GitSubmoduleUpdateArgs) error
+	ListSubmodules(repoDir string, recursive bool) ([]GitSubmodule, error)
+	ListDeclaredSubmodules(repoDir string) ([]GitSubmodule, error)
+	GetCommitInfo(repoDir, ref string) (*GitCommitInfo, error)
+	Describe(repoDir string) (string, error)
+	CreateBundle(repoDir, bundlePath string) error
+	GetCommitSignature(repoDir, ref string) (*GitCommitSignature, error)
+	IsLfsAvailable() bool
+	LfsInstall(repoDir string) error
+	LfsPull(args *GitLfsPullArgs) error
+	SetEnv(name, value string)
+	AddConfig(key, value string)
 }
 
 var _ GitCliInterface = &GitCli{}
 
+// FullShaRegex matches full commit hash of sha1 and sha256 repositories.
+var FullShaRegex = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
 type GitCli struct {
 	Executor CliExecutorInterface
 	Verbose  bool
+	// Env holds additional environment variables for git commands in "NAME=value" form.
+	Env []string
+
+	configCount int
 }
 
 func NewGitCli(executor CliExecutorInterface, verbose bool) (*GitCli, error) {
@@ -38,24 +73,93 @@ func NewGitCli(executor CliExecutorInterface, verbose bool) (*GitCli, error) {
 	}, nil
 }
 
+// SetEnv sets an environment variable for all subsequent git invocations.
+// The environment of the CLI process itself is not modified.
+func (g *GitCli) SetEnv(name, value string) {
+	prefix := name + "="
+	for i, envVar := range g.Env {
+		if strings.HasPrefix(envVar, prefix) {
+			g.Env[i] = prefix + value
+			return
+		}
+	}
+	g.Env = append(g.Env, prefix+value)
+}
+
+// AddConfig adds a git configuration option for all subsequent git invocations.
+// The option is passed via environment, so no git config files are modified.
+func (g *GitCli) AddConfig(key, value string) {
+	g.SetEnv(fmt.Sprintf("GIT_CONFIG_KEY_%d", g.configCount), key)
+	g.SetEnv(fmt.Sprintf("GIT_CONFIG_VALUE_%d", g.configCount), value)
+	g.configCount++
+	g.SetEnv("GIT_CONFIG_COUNT", strconv.Itoa(g.configCount))
+}
+
+// executor returns executor that runs commands with git specific environment.
+func (g *GitCli) executor() CliExecutorInterface {
+	if len(g.Env) == 0 {
+		return g.Executor
+	}
+	return g.Executor.WithEnv(g.Env)
+}
+
+type GitCloneArgs struct {
+	Url    string
+	Branch string
+	Depth  int
+	// Directory to clone into. If not set, git derives it from the url.
+	Directory string
+	// Filter is a partial clone filter spec, e.g. blob:none
+	Filter string
+	// Sparse initializes sparse checkout with only top level files checked out.
+	Sparse bool
+	// Mirror creates a bare mirror of the repository with all refs. Branch and Depth are ignored.
+	Mirror bool
+	// Reference is a local repository to borrow objects from, if it's usable.
+	Reference string
+	// Dissociate copies borrowed objects from the reference repository after clone.
+	Dissociate bool
+}
+
 // Clone clones given git repository and returns path to the repository root folder.
 // Returns name of the clonned source directory.
-func (g *GitCli) Clone(url, branch string, depth int) (string, error) {
-	if url == "" {
+func (g *GitCli) Clone(args *GitCloneArgs) (string, error) {
+	if args.Url == "" {
 		return "", errors.New("url must be set to clone")
 	}
-	gitArgs := []string{"clone", url}
+	gitArgs := []string{"clone"}
 
-	if branch == "" {
-		branch = "main"
-	}
-	gitArgs = append(gitArgs, "--branch", branch)
+	if args.Mirror {
+		gitArgs = append(gitArgs, "--mirror")
+	} else {
+		// Without branch, git checks out the remote HEAD.
+		if args.Branch != "" {
+			gitArgs = append(gitArgs, "--branch", args.Branch)
+		}
 
-	if depth != 0 {
-		gitArgs = append(gitArgs, "--depth", strconv.Itoa(depth))
+		if args.Depth != 0 {
+			gitArgs = append(gitArgs, "--depth", strconv.Itoa(args.Depth))
+		}
+	}
+	if args.Filter != "" {
+		gitArgs = append(gitArgs, "--filter", args.Filter)
+	}
+	if args.Sparse {
+		gitArgs = append(gitArgs, "--sparse")
+	}
+	if args.Reference != "" {
+		gitArgs = append(gitArgs, "--reference-if-able", args.Reference)
+		if args.Dissociate {
+			gitArgs = append(gitArgs, "--dissociate")
+		}
+	}
+	// The url and directory are separated from options, so they are never taken as options.
+	gitArgs = append(gitArgs, "--", args.Url)
+	if args.Directory != "" {
+		gitArgs = append(gitArgs, args.Directory)
 	}
 
-	stdout, stderr, _, err := g.Executor.Execute("git", gitArgs...)
+	stdout, stderr, _, err := g.executor().Execute("git", gitArgs...)
 	if err != nil {
 		l.Logger.Errorf("[stdout]:\n%s", stdout)
 		l.Logger.Errorf("[stderr]:\n%s", stderr)
@@ -66,6 +170,10 @@ func (g *GitCli) Clone(url, branch string, depth int) (string, error) {
 		l.Logger.Info("[stdout]:\n" + stderr)
 	}
 
+	if args.Directory != "" {
+		return args.Directory, nil
+	}
+
 	// Parse output for "Cloning into 'repository-name'..."
 	repoDir, err := parseRepoDir(stderr)
 	if err != nil {
@@ -92,7 +200,7 @@ func parseRepoDir(output string) (string, error) {
 }
 
 func (g *GitCli) GetRepoHeadFullSha(gitRepoDir string) (string, error) {
-	stdout, stderr, _, err := g.Executor.ExecuteInDir(gitRepoDir, "git", "rev-parse", "HEAD")
+	stdout, stderr, _, err := g.executor().ExecuteInDir(gitRepoDir, "git", "rev-parse", "HEAD")
 	if err != nil {
 		l.Logger.Errorf("[stdout]:\n%s", stdout)
 		l.Logger.Errorf("[stderr]:\n%s", stderr)
@@ -106,3 +214,500 @@ func (g *GitCli) GetRepoHeadFullSha(gitRepoDir string) (string, error) {
 	fullSha := strings.TrimSpace(string(stdout))
 	return fullSha, nil
 }
+
+// runGit executes git with given arguments in the specified repository directory.
+// Returns stdout of the command.
+func (g *GitCli) runGit(repoDir string, args ...string) (string, error) {
+	stdout, stderr, _, err := g.executor().ExecuteInDir(repoDir, "git", args...)
+	if err != nil {
+		l.Logger.Errorf("[stdout]:\n%s", stdout)
+		l.Logger.Errorf("[stderr]:\n%s", stderr)
+		return "", fmt.Errorf("git %s failed: %v", getGitCommandName(args), err)
+	}
+
+	if g.Verbose && stdout != "" {
+		l.Logger.Info("[stdout]:\n" + stdout)
+	}
+
+	return stdout, nil
+}
+
+// getGitCommandName returns git subcommand of the given arguments, skipping leading config options,
+// e.g. "bundle" for "-c pack.threads=1 bundle create".
+func getGitCommandName(args []string) string {
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-c" {
+			i++
+			continue
+		}
+		return args[i]
+	}
+	return ""
+}
+
+// Init creates an empty git repository in the given directory.
+// The directory is created if it doesn't exist.
+func (g *GitCli) Init(repoDir string) error {
+	if repoDir == "" {
+		return errors.New("repository directory must be set")
+	}
+	_, err := g.runGit("", "init", repoDir)
+	return err
+}
+
+// AddRemote adds a remote with given name and url to the repository.
+func (g *GitCli) AddRemote(repoDir, name, url string) error {
+	if name == "" || url == "" {
+		return errors.New("remote name and url must be set")
+	}
+	_, err := g.runGit(repoDir, "remote", "add", "--", name, url)
+	return err
+}
+
+type GitFetchArgs struct {
+	RepoDir  string
+	Remote   string
+	Refspecs []string
+	Depth    int
+	// Filter is a partial clone filter spec, e.g. blob:none
+	Filter string
+	// Unshallow fetches the missing history of a shallow repository.
+	Unshallow bool
+	// Prune removes local refs that no longer exist on the remote.
+	Prune bool
+	// Deepen fetches the given number of additional commits of a shallow repository history.
+	Deepen int
+}
+
+// Fetch fetches given refspecs from the remote.
+// Refspec could be a branch, tag, full commit SHA or any other ref, e.g. refs/pull/123/head.
+// The last fetched ref is available as FETCH_HEAD.
+func (g *GitCli) Fetch(args *GitFetchArgs) error {
+	if args.Remote == "" {
+		return errors.New("remote to fetch from must be set")
+	}
+
+	gitArgs := []string{"fetch"}
+	if args.Depth != 0 {
+		gitArgs = append(gitArgs, "--depth", strconv.Itoa(args.Depth))
+	}
+	if args.Filter != "" {
+		gitArgs = append(gitArgs, "--filter", args.Filter)
+	}
+	if args.Unshallow {
+		gitArgs = append(gitArgs, "--unshallow")
+	}
+	if args.Prune {
+		gitArgs = append(gitArgs, "--prune")
+	}
+	if args.Deepen != 0 {
+		gitArgs = append(gitArgs, "--deepen", strconv.Itoa(args.Deepen))
+	}
+	// The remote could be an url
+	gitArgs = append(gitArgs, "--", args.Remote)
+	gitArgs = append(gitArgs, args.Refspecs...)
+
+	_, err := g.runGit(args.RepoDir, gitArgs...)
+	return err
+}
+
+// Checkout checks out the given ref in the repository.
+func (g *GitCli) Checkout(repoDir, ref string) error {
+	if ref == "" {
+		return errors.New("ref to checkout must be set")
+	}
+	_, err := g.runGit(repoDir, "checkout", ref)
+	return err
+}
+
+// GetRemoteUrl returns url of the given remote.
+func (g *GitCli) GetRemoteUrl(repoDir, remote string) (string, error) {
+	stdout, err := g.runGit(repoDir, "remote", "get-url", remote)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// GetRemoteDefaultBranch returns the branch the HEAD of the remote repository points to.
+func (g *GitCli) GetRemoteDefaultBranch(url string) (string, error) {
+	if url == "" {
+		return "", errors.New("url must be set")
+	}
+	stdout, err := g.runGit("", "ls-remote", "--symref", "--", url, "HEAD")
+	if err != nil {
+		return "", err
+	}
+	// The symref line looks like: "ref: refs/heads/main<TAB>HEAD"
+	for _, line := range strings.Split(stdout, "\n") {
+		target, ok := strings.CutPrefix(line, "ref: ")
+		if !ok {
+			continue
+		}
+		ref, _, _ := strings.Cut(target, "\t")
+		if branch, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+			return branch, nil
+		}
+	}
+	return "", errors.New("remote HEAD doesn't point to a branch")
+}
+
+// ResetHard resets the current branch, index and working tree to the given ref.
+func (g *GitCli) ResetHard(repoDir, ref string) error {
+	if ref == "" {
+		return errors.New("ref to reset to must be set")
+	}
+	_, err := g.runGit(repoDir, "reset", "--hard", ref)
+	return err
+}
+
+// Clean removes all untracked and ignored files and directories, including nested repositories.
+func (g *GitCli) Clean(repoDir string) error {
+	_, err := g.runGit(repoDir, "clean", "-ffdx")
+	return err
+}
+
+// Repack packs all objects, including borrowed from alternates, into the repository.
+func (g *GitCli) Repack(repoDir string) error {
+	_, err := g.runGit(repoDir, "repack", "-a", "-d", "-q")
+	return err
+}
+
+func (g *GitCli) IsBareRepository(repoDir string) (bool, error) {
+	stdout, err := g.runGit(repoDir, "rev-parse", "--is-bare-repository")
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(stdout) == "true", nil
+}
+
+func (g *GitCli) IsShallowRepository(repoDir string) (bool, error) {
+	stdout, err := g.runGit(repoDir, "rev-parse", "--is-shallow-repository")
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(stdout) == "true", nil
+}
+
+// MergeBase returns the best common ancestor of the given commits.
+// Returns empty string if the commits have no common ancestor in the available history.
+func (g *GitCli) MergeBase(repoDir, ref1, ref2 string) (string, error) {
+	stdout, stderr, exitCode, err := g.executor().ExecuteInDir(repoDir, "git", "merge-base", ref1, ref2)
+	if err != nil {
+		if exitCode == 1 && stderr == "" {
+			return "", nil
+		}
+		l.Logger.Errorf("[stdout]:\n%s", stdout)
+		l.Logger.Errorf("[stderr]:\n%s", stderr)
+		return "", fmt.Errorf("git merge-base failed: %v", err)
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// DiffNames returns paths of files that differ between the given commits.
+func (g *GitCli) DiffNames(repoDir, fromRef, toRef string) ([]string, error) {
+	stdout, err := g.runGit(repoDir, "diff", "--name-only", "--no-renames", "-z", fromRef, toRef)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{}
+	for _, path := range strings.Split(stdout, "\x00") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// SparseCheckoutSet enables cone mode sparse checkout and limits the working tree to the given directories.
+// Files in the repository root are always checked out in cone mode.
+func (g *GitCli) SparseCheckoutSet(repoDir string, paths []string) error {
+	if len(paths) == 0 {
+		return errors.New("sparse checkout paths must be set")
+	}
+	gitArgs := []string{"sparse-checkout", "set", "--cone"}
+	gitArgs = append(gitArgs, paths...)
+
+	_, err := g.runGit(repoDir, gitArgs...)
+	return err
+}
+
+type GitMergeArgs struct {
+	RepoDir string
+	Ref     string
+	Message string
+	// Identity of the merge commit author and committer
+	UserName  string
+	UserEmail string
+}
+
+// Merge merges the given ref into the current HEAD creating a merge commit.
+// Fails with the list of conflicting files if the merge cannot be done automatically.
+func (g *GitCli) Merge(args *GitMergeArgs) error {
+	if args.Ref == "" {
+		return errors.New("ref to merge must be set")
+	}
+
+	gitArgs := []string{}
+	if args.UserName != "" {
+		gitArgs = append(gitArgs, "-c", "user.name="+args.UserName)
+	}
+	if args.UserEmail != "" {
+		gitArgs = append(gitArgs, "-c", "user.email="+args.UserEmail)
+	}
+	gitArgs = append(gitArgs, "merge", "--no-edit", "--no-ff")
+	if args.Message != "" {
+		gitArgs = append(gitArgs, "-m", args.Message)
+	}
+	gitArgs = append(gitArgs, args.Ref)
+
+	stdout, stderr, _, err := g.executor().ExecuteInDir(args.RepoDir, "git", gitArgs...)
+	if err != nil {
+		l.Logger.Errorf("[stdout]:\n%s", stdout)
+		l.Logger.Errorf("[stderr]:\n%s", stderr)
+
+		conflicts, conflictsErr := g.runGit(args.RepoDir, "diff", "--name-only", "--diff-filter=U")
+		if conflictsErr == nil && strings.TrimSpace(conflicts) != "" {
+			conflictingFiles := strings.Split(strings.TrimSpace(conflicts), "\n")
+			return fmt.Errorf("git merge failed due to conflicts in: %s", strings.Join(conflictingFiles, ", "))
+		}
+		return fmt.Errorf("git merge failed: %v", err)
+	}
+
+	if g.Verbose {
+		l.Logger.Info("[stdout]:\n" + stdout)
+	}
+
+	return nil
+}
+
+type GitSubmoduleUpdateArgs struct {
+	RepoDir   string
+	Recursive bool
+	Depth     int
+	// Paths limits the update to the given submodules. All submodules are updated if empty.
+	Paths []string
+}
+
+// SubmoduleUpdate initializes and checks out submodules of the repository.
+func (g *GitCli) SubmoduleUpdate(args *GitSubmoduleUpdateArgs) error {
+	gitArgs := []string{"submodule", "update", "--init"}
+	if args.Recursive {
+		gitArgs = append(gitArgs, "--recursive")
+	}
+	if args.Depth != 0 {
+		gitArgs = append(gitArgs, "--depth", strconv.Itoa(args.Depth))
+	}
+	if len(args.Paths) != 0 {
+		gitArgs = append(gitArgs, "--")
+		gitArgs = append(gitArgs, args.Paths...)
+	}
+
+	_, err := g.runGit(args.RepoDir, gitArgs...)
+	return err
+}
+
+type GitSubmodule struct {
+	Path   string `json:"path"`
+	Url    string `json:"url"`
+	Commit string `json:"commit"`
+}
+
+// ListSubmodules returns information about checked out submodules of the repository.
+// Submodule path is relative to the repository root.
+func (g *GitCli) ListSubmodules(repoDir string, recursive bool) ([]GitSubmodule, error) {
+	gitArgs := []string{"submodule", "foreach", "--quiet"}
+	if recursive {
+		gitArgs = append(gitArgs, "--recursive")
+	}
+	// The script is evaluated by git in each submodule directory.
+	gitArgs = append(gitArgs, `printf '%s\t%s\t%s\n' "$displaypath" "$(git remote get-url origin)" "$(git rev-parse HEAD)"`)
+
+	stdout, err := g.runGit(repoDir, gitArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	submodules := []GitSubmodule{}
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("failed to parse submodule info: '%s'", line)
+		}
+		submodules = append(submodules, GitSubmodule{
+			Path:   fields[0],
+			Url:    fields[1],
+			Commit: fields[2],
+		})
+	}
+	return submodules, nil
+}
+
+// ListDeclaredSubmodules returns submodules declared in .gitmodules file of the repository
+// without accessing them, so it can be used before the submodules are fetched.
+// Url is returned as declared, it might be relative to the repository url. Commit is not set.
+func (g *GitCli) ListDeclaredSubmodules(repoDir string) ([]GitSubmodule, error) {
+	gitArgs := []string{"config", "--file", ".gitmodules", "--get-regexp", `^submodule\..*\.(path|url)$`}
+	stdout, stderr, exitCode, err := g.executor().ExecuteInDir(repoDir, "git", gitArgs...)
+	if err != nil {
+		// Missing .gitmodules file or no submodules in it
+		if exitCode == 1 && stderr == "" {
+			return []GitSubmodule{}, nil
+		}
+		l.Logger.Errorf("[stdout]:\n%s", stdout)
+		l.Logger.Errorf("[stderr]:\n%s", stderr)
+		return nil, fmt.Errorf("git config failed: %v", err)
+	}
+
+	// Submodule name might contain dots, so the entries are grouped by the key without the last segment.
+	submodules := []GitSubmodule{}
+	indexes := map[string]int{}
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		if line == "" {
+			continue
+		}
+		key, value, found := strings.Cut(line, " ")
+		lastDot := strings.LastIndex(key, ".")
+		if !found || lastDot == -1 {
+			return nil, fmt.Errorf("failed to parse submodule config: '%s'", line)
+		}
+		name, field := key[:lastDot], key[lastDot+1:]
+		index, exists := indexes[name]
+		if !exists {
+			index = len(submodules)
+			indexes[name] = index
+			submodules = append(submodules, GitSubmodule{})
+		}
+		switch field {
+		case "path":
+			submodules[index].Path = value
+		case "url":
+			submodules[index].Url = value
+		}
+	}
+	return submodules, nil
+}
+
+// IsLfsAvailable checks if git-lfs extension is installed.
+func (g *GitCli) IsLfsAvailable() bool {
+	lfsAvailable, err := CheckCliToolAvailable("git-lfs")
+	return err == nil && lfsAvailable
+}
+
+// LfsInstall configures git-lfs hooks and filters in the repository only.
+func (g *GitCli) LfsInstall(repoDir string) error {
+	_, err := g.runGit(repoDir, "lfs", "install", "--local")
+	return err
+}
+
+type GitLfsPullArgs struct {
+	RepoDir string
+	// Include and Exclude are lists of paths or patterns to filter LFS objects to download.
+	Include []string
+	Exclude []string
+}
+
+// LfsPull downloads LFS objects for the current checkout and replaces pointer files with the content.
+func (g *GitCli) LfsPull(args *GitLfsPullArgs) error {
+	gitArgs := []string{"lfs", "pull"}
+	if len(args.Include) != 0 {
+		gitArgs = append(gitArgs, "--include", strings.Join(args.Include, ","))
+	}
+	if len(args.Exclude) != 0 {
+		gitArgs = append(gitArgs, "--exclude", strings.Join(args.Exclude, ","))
+	}
+
+	_, err := g.runGit(args.RepoDir, gitArgs...)
+	return err
+}
+
+type GitCommitInfo struct {
+	// Author in "Name <email>" form
+	Author        string
+	CommitterDate time.Time
+	Subject       string
+}
+
+// GetCommitInfo returns metadata of the given commit.
+func (g *GitCli) GetCommitInfo(repoDir, ref string) (*GitCommitInfo, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	stdout, err := g.runGit(repoDir, "log", "-1", "--format=%an <%ae>%n%ct%n%s", ref)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.SplitN(strings.TrimRight(stdout, "\n"), "\n", 3)
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("failed to parse commit info: '%s'", stdout)
+	}
+	committerTimestamp, err := strconv.ParseInt(lines[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse commit timestamp '%s': %w", lines[1], err)
+	}
+	commitInfo := &GitCommitInfo{
+		Author:        lines[0],
+		CommitterDate: time.Unix(committerTimestamp, 0).UTC(),
+	}
+	if len(lines) == 3 {
+		commitInfo.Subject = lines[2]
+	}
+	return commitInfo, nil
+}
+
+// GitSignatureStatusGood is the git signature status of a good signature made by a trusted key.
+const GitSignatureStatusGood = "G"
+
+type GitCommitSignature struct {
+	// Status is the git signature status, see %G? in git log pretty formats.
+	Status string
+	// Signer is GPG key user id or the principal from SSH allowed signers file.
+	Signer      string
+	Fingerprint string
+}
+
+// GetCommitSignature verifies signature of the given commit against configured GPG keyring or SSH allowed signers.
+// A commit without signature is not an error, it's reported via "N" status.
+func (g *GitCli) GetCommitSignature(repoDir, ref string) (*GitCommitSignature, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	stdout, err := g.runGit(repoDir, "log", "-1", "--format=%G?%n%GS%n%GF", ref)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(lines) != 3 || lines[0] == "" {
+		return nil, fmt.Errorf("failed to parse commit signature: '%s'", stdout)
+	}
+	return &GitCommitSignature{
+		Status:      lines[0],
+		Signer:      lines[1],
+		Fingerprint: lines[2],
+	}, nil
+}
+
+// Describe returns the most recent tag reachable from HEAD with the commit distance suffix, if any.
+// Falls back to abbreviated commit SHA if no tags found.
+func (g *GitCli) Describe(repoDir string) (string, error) {
+	stdout, err := g.runGit(repoDir, "describe", "--tags", "--always")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// CreateBundle writes HEAD commit with its history into a git bundle file.
+// Single threaded packing is used to make the bundle reproducible.
+func (g *GitCli) CreateBundle(repoDir, bundlePath string) error {
+	if bundlePath == "" {
+		return errors.New("bundle path must be set")
+	}
+	_, err := g.runGit(repoDir, "-c", "pack.threads=1", "bundle", "create", bundlePath, "HEAD")
+	return err
+}
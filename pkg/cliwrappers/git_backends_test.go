@@ -0,0 +1,319 @@
+package cliwrappers_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	. "github.com/onsi/gomega"
+
+	"github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+)
+
+// testRemoteRepo is a local repository used as the remote in backend tests:
+//
+//	main:    c1 (tag v1.0.0) - c2 - c3
+//	feature:                   c2 - f1 - f2
+type testRemoteRepo struct {
+	url string
+	c1  string
+	c2  string
+	c3  string
+	f2  string
+}
+
+var testSignature = &object.Signature{
+	Name:  "Test User",
+	Email: "test@example.com",
+	When:  time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+}
+
+func commitFile(g *WithT, repo *git.Repository, repoDir, path, content, message string) string {
+	g.Expect(os.MkdirAll(filepath.Dir(filepath.Join(repoDir, path)), 0755)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(repoDir, path), []byte(content), 0644)).To(Succeed())
+	worktree, err := repo.Worktree()
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = worktree.Add(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	hash, err := worktree.Commit(message, &git.CommitOptions{Author: testSignature, Committer: testSignature})
+	g.Expect(err).ToNot(HaveOccurred())
+	return hash.String()
+}
+
+func createTestRemoteRepo(t *testing.T) *testRemoteRepo {
+	g := NewWithT(t)
+
+	repoDir := t.TempDir()
+	repo, err := git.PlainInitWithOptions(repoDir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.Main},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	// Allow fetching by commit SHA, like hosting services do
+	repoConfig, err := repo.Config()
+	g.Expect(err).ToNot(HaveOccurred())
+	repoConfig.Raw.Section("uploadpack").SetOption("allowReachableSHA1InWant", "true")
+	g.Expect(repo.SetConfig(repoConfig)).To(Succeed())
+
+	remote := &testRemoteRepo{url: "file://" + repoDir}
+	remote.c1 = commitFile(g, repo, repoDir, "README.md", "readme", "Initial commit")
+	_, err = repo.CreateTag("v1.0.0", plumbing.NewHash(remote.c1), nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	remote.c2 = commitFile(g, repo, repoDir, "main.go", "package main", "Add main")
+
+	worktree, err := repo.Worktree()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true})).To(Succeed())
+	commitFile(g, repo, repoDir, "svc/a.txt", "a", "Add a")
+	remote.f2 = commitFile(g, repo, repoDir, "svc/b.txt", "b", "Add b")
+
+	g.Expect(worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.Main})).To(Succeed())
+	remote.c3 = commitFile(g, repo, repoDir, "main.go", "package main\n", "Fix main")
+
+	return remote
+}
+
+func getGitBackends(t *testing.T) map[string]cliwrappers.GitCliInterface {
+	backends := map[string]cliwrappers.GitCliInterface{
+		"go-git": cliwrappers.NewGoGit(false),
+	}
+	if isGitAvailable, _ := cliwrappers.CheckCliToolAvailable("git"); isGitAvailable {
+		backends["cli"] = &cliwrappers.GitCli{Executor: cliwrappers.NewCliExecutor(false)}
+	} else {
+		t.Log("git CLI is not available, testing go-git backend only")
+	}
+	return backends
+}
+
+func runForGitBackends(t *testing.T, test func(t *testing.T, g *WithT, gitCli cliwrappers.GitCliInterface, remote *testRemoteRepo)) {
+	for name, gitCli := range getGitBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			test(t, NewWithT(t), gitCli, createTestRemoteRepo(t))
+		})
+	}
+}
+
+func TestGitBackends_Clone(t *testing.T) {
+	runForGitBackends(t, func(t *testing.T, g *WithT, gitCli cliwrappers.GitCliInterface, remote *testRemoteRepo) {
+		repoDir := filepath.Join(t.TempDir(), "repo")
+
+		clonedDir, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: remote.url, Branch: "main", Directory: repoDir})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(clonedDir).To(Equal(repoDir))
+
+		sha, err := gitCli.GetRepoHeadFullSha(repoDir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(sha).To(Equal(remote.c3))
+
+		remoteUrl, err := gitCli.GetRemoteUrl(repoDir, "origin")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(remoteUrl).To(Equal(remote.url))
+
+		isShallow, err := gitCli.IsShallowRepository(repoDir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(isShallow).To(BeFalse())
+
+		isBare, err := gitCli.IsBareRepository(repoDir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(isBare).To(BeFalse())
+	})
+}
+
+func TestGitBackends_Clone_BranchWithDepth(t *testing.T) {
+	runForGitBackends(t, func(t *testing.T, g *WithT, gitCli cliwrappers.GitCliInterface, remote *testRemoteRepo) {
+		repoDir := filepath.Join(t.TempDir(), "repo")
+
+		_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: remote.url, Branch: "feature", Depth: 1, Directory: repoDir})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		sha, err := gitCli.GetRepoHeadFullSha(repoDir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(sha).To(Equal(remote.f2))
+
+		isShallow, err := gitCli.IsShallowRepository(repoDir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(isShallow).To(BeTrue())
+		g.Expect(filepath.Join(repoDir, "svc", "b.txt")).To(BeARegularFile())
+	})
+}
+
+func TestGitBackends_Clone_Tag(t *testing.T) {
+	runForGitBackends(t, func(t *testing.T, g *WithT, gitCli cliwrappers.GitCliInterface, remote *testRemoteRepo) {
+		repoDir := filepath.Join(t.TempDir(), "repo")
+
+		_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: remote.url, Branch: "v1.0.0", Depth: 1, Directory: repoDir})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		sha, err := gitCli.GetRepoHeadFullSha(repoDir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(sha).To(Equal(remote.c1))
+	})
+}
+
+func TestGitBackends_Clone_NonexistentBranch(t *testing.T) {
+	runForGitBackends(t, func(t *testing.T, g *WithT, gitCli cliwrappers.GitCliInterface, remote *testRemoteRepo) {
+		repoDir := filepath.Join(t.TempDir(), "repo")
+
+		_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: remote.url, Branch: "nonexistent", Directory: repoDir})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("git clone failed"))
+	})
+}
+
+func TestGitBackends_RemoteDefaultBranch(t *testing.T) {
+	runForGitBackends(t, func(t *testing.T, g *WithT, gitCli cliwrappers.GitCliInterface, remote *testRemoteRepo) {
+		// Point the remote HEAD to a branch other than main
+		remoteRepo, err := git.PlainOpen(strings.TrimPrefix(remote.url, "file://"))
+		g.Expect(err).ToNot(HaveOccurred())
+		headRef := plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("feature"))
+		g.Expect(remoteRepo.Storer.SetReference(headRef)).To(Succeed())
+
+		branch, err := gitCli.GetRemoteDefaultBranch(remote.url)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(branch).To(Equal("feature"))
+
+		// Clone without branch checks out the remote HEAD
+		repoDir := filepath.Join(t.TempDir(), "repo")
+		_, err = gitCli.Clone(&cliwrappers.GitCloneArgs{Url: remote.url, Depth: 1, Directory: repoDir})
+		g.Expect(err).ToNot(HaveOccurred())
+		sha, err := gitCli.GetRepoHeadFullSha(repoDir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(sha).To(Equal(remote.f2))
+	})
+}
+
+func TestGitBackends_OptionLikeUrlIsNotExecuted(t *testing.T) {
+	runForGitBackends(t, func(t *testing.T, g *WithT, gitCli cliwrappers.GitCliInterface, remote *testRemoteRepo) {
+		marker := filepath.Join(t.TempDir(), "pwned")
+		url := "--upload-pack=touch " + marker + ";:x"
+
+		_, err := gitCli.GetRemoteDefaultBranch(url)
+		g.Expect(err).To(HaveOccurred())
+		_, err = gitCli.Clone(&cliwrappers.GitCloneArgs{Url: url, Directory: filepath.Join(t.TempDir(), "repo")})
+		g.Expect(err).To(HaveOccurred())
+
+		_, err = os.Stat(marker)
+		g.Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+}
+
+func TestGitBackends_Clone_Mirror(t *testing.T) {
+	runForGitBackends(t, func(t *testing.T, g *WithT, gitCli cliwrappers.GitCliInterface, remote *testRemoteRepo) {
+		mirrorDir := filepath.Join(t.TempDir(), "mirror.git")
+
+		_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: remote.url, Directory: mirrorDir, Mirror: true})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		isBare, err := gitCli.IsBareRepository(mirrorDir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(isBare).To(BeTrue())
+
+		g.Expect(gitCli.Fetch(&cliwrappers.GitFetchArgs{RepoDir: mirrorDir, Remote: "origin", Prune: true})).To(Succeed())
+	})
+}
+
+func TestGitBackends_FetchRevision(t *testing.T) {
+	runForGitBackends(t, func(t *testing.T, g *WithT, gitCli cliwrappers.GitCliInterface, remote *testRemoteRepo) {
+		repoDir := filepath.Join(t.TempDir(), "repo")
+
+		g.Expect(gitCli.Init(repoDir)).To(Succeed())
+		g.Expect(gitCli.AddRemote(repoDir, "origin", remote.url)).To(Succeed())
+		fetchArgs := &cliwrappers.GitFetchArgs{RepoDir: repoDir, Remote: "origin", Refspecs: []string{remote.c2}, Depth: 1}
+		g.Expect(gitCli.Fetch(fetchArgs)).To(Succeed())
+		g.Expect(gitCli.Checkout(repoDir, "FETCH_HEAD")).To(Succeed())
+
+		sha, err := gitCli.GetRepoHeadFullSha(repoDir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(sha).To(Equal(remote.c2))
+		g.Expect(filepath.Join(repoDir, "main.go")).To(BeARegularFile())
+	})
+}
+
+func TestGitBackends_FetchBranchAndResetHard(t *testing.T) {
+	runForGitBackends(t, func(t *testing.T, g *WithT, gitCli cliwrappers.GitCliInterface, remote *testRemoteRepo) {
+		repoDir := filepath.Join(t.TempDir(), "repo")
+
+		_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: remote.url, Branch: "main", Directory: repoDir})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("modified"), 0644)).To(Succeed())
+
+		fetchArgs := &cliwrappers.GitFetchArgs{RepoDir: repoDir, Remote: "origin", Refspecs: []string{"feature"}}
+		g.Expect(gitCli.Fetch(fetchArgs)).To(Succeed())
+		g.Expect(gitCli.ResetHard(repoDir, "FETCH_HEAD")).To(Succeed())
+
+		sha, err := gitCli.GetRepoHeadFullSha(repoDir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(sha).To(Equal(remote.f2))
+		g.Expect(os.ReadFile(filepath.Join(repoDir, "README.md"))).To(Equal([]byte("readme")))
+	})
+}
+
+func TestGitBackends_MergeBaseAndDiffNames(t *testing.T) {
+	runForGitBackends(t, func(t *testing.T, g *WithT, gitCli cliwrappers.GitCliInterface, remote *testRemoteRepo) {
+		repoDir := filepath.Join(t.TempDir(), "repo")
+
+		_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: remote.url, Branch: "feature", Directory: repoDir})
+		g.Expect(err).ToNot(HaveOccurred())
+		fetchArgs := &cliwrappers.GitFetchArgs{RepoDir: repoDir, Remote: "origin", Refspecs: []string{"+main:refs/konflux/base"}}
+		g.Expect(gitCli.Fetch(fetchArgs)).To(Succeed())
+
+		mergeBase, err := gitCli.MergeBase(repoDir, "HEAD", "refs/konflux/base")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(mergeBase).To(Equal(remote.c2))
+
+		changedFiles, err := gitCli.DiffNames(repoDir, mergeBase, "HEAD")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(changedFiles).To(Equal([]string{"svc/a.txt", "svc/b.txt"}))
+	})
+}
+
+func TestGitBackends_CommitMetadata(t *testing.T) {
+	runForGitBackends(t, func(t *testing.T, g *WithT, gitCli cliwrappers.GitCliInterface, remote *testRemoteRepo) {
+		repoDir := filepath.Join(t.TempDir(), "repo")
+
+		_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: remote.url, Branch: "main", Directory: repoDir})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		commitInfo, err := gitCli.GetCommitInfo(repoDir, "HEAD")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(commitInfo.Author).To(Equal("Test User <test@example.com>"))
+		g.Expect(commitInfo.CommitterDate).To(Equal(testSignature.When))
+		g.Expect(commitInfo.Subject).To(Equal("Fix main"))
+
+		describe, err := gitCli.Describe(repoDir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(describe).To(Equal("v1.0.0-2-g" + remote.c3[:7]))
+	})
+}
+
+func TestGoGit_UnsupportedSettings(t *testing.T) {
+	g := NewWithT(t)
+	remote := createTestRemoteRepo(t)
+
+	goGit := cliwrappers.NewGoGit(false)
+	goGit.SetEnv("GIT_TERMINAL_PROMPT", "0")
+	goGit.AddConfig("safe.directory", "/workspace/source")
+	_, err := goGit.Clone(&cliwrappers.GitCloneArgs{Url: remote.url, Branch: "main", Directory: filepath.Join(t.TempDir(), "repo")})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	goGit.AddConfig("credential.helper", "store --file=/tmp/credentials")
+	_, err = goGit.Clone(&cliwrappers.GitCloneArgs{Url: remote.url, Branch: "main", Directory: filepath.Join(t.TempDir(), "repo")})
+	g.Expect(err).To(MatchError(cliwrappers.ErrGoGitUnsupported))
+	g.Expect(err.Error()).To(ContainSubstring("credential.helper config"))
+}
+
+func TestGoGit_UnsupportedOperations(t *testing.T) {
+	g := NewWithT(t)
+
+	goGit := cliwrappers.NewGoGit(false)
+	g.Expect(goGit.IsLfsAvailable()).To(BeFalse())
+	g.Expect(goGit.Merge(&cliwrappers.GitMergeArgs{RepoDir: "repo", Ref: "FETCH_HEAD"})).To(MatchError(cliwrappers.ErrGoGitUnsupported))
+	g.Expect(goGit.SparseCheckoutSet("repo", []string{"docs"})).To(MatchError(cliwrappers.ErrGoGitUnsupported))
+	g.Expect(goGit.CreateBundle("repo", "repo.bundle")).To(MatchError(cliwrappers.ErrGoGitUnsupported))
+	_, err := goGit.Clone(&cliwrappers.GitCloneArgs{Url: "file:///repo", Filter: "blob:none"})
+	g.Expect(err).To(MatchError(cliwrappers.ErrGoGitUnsupported))
+}
@@ -2,7 +2,9 @@ package cliwrappers_test
 
 import (
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 
@@ -30,14 +32,11 @@ func TestGitCli_Clone_DefaultBranch(t *testing.T) {
 		return stdout, stderr, 0, nil
 	}
 
-	_, err := gitCli.Clone("https://github.com/test/repo.git", "", 0)
+	_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "https://github.com/test/repo.git"})
 
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(capturedArgs).To(HaveLen(4))
-	g.Expect(capturedArgs).To(ContainElement("clone"))
-	g.Expect(capturedArgs).To(ContainElement("https://github.com/test/repo.git"))
-	g.Expect(capturedArgs).To(ContainElement("--branch"))
-	g.Expect(capturedArgs).To(ContainElement("main"))
+	// Without branch git checks out the remote HEAD
+	g.Expect(capturedArgs).To(Equal([]string{"clone", "--", "https://github.com/test/repo.git"}))
 }
 
 func TestGitCli_Clone_SpecifiedBranch(t *testing.T) {
@@ -51,7 +50,7 @@ func TestGitCli_Clone_SpecifiedBranch(t *testing.T) {
 		return stdout, stderr, 0, nil
 	}
 
-	_, err := gitCli.Clone("https://github.com/test/repo.git", "devel", 0)
+	_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "https://github.com/test/repo.git", Branch: "devel"})
 
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(capturedArgs).To(ContainElement("--branch"))
@@ -69,7 +68,7 @@ func TestGitCli_Clone_WithDepth(t *testing.T) {
 		return stdout, stderr, 0, nil
 	}
 
-	_, err := gitCli.Clone("https://github.com/test/repo.git", "main", 5)
+	_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "https://github.com/test/repo.git", Branch: "main", Depth: 5})
 
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(capturedArgs).To(ContainElement("--depth"))
@@ -87,7 +86,7 @@ func TestGitCli_Clone_NoDepthWhenZero(t *testing.T) {
 		return stdout, stderr, 0, nil
 	}
 
-	_, err := gitCli.Clone("https://github.com/test/repo.git", "main", 0)
+	_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "https://github.com/test/repo.git", Branch: "main"})
 
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(capturedArgs).NotTo(ContainElement("--depth"))
@@ -102,7 +101,7 @@ func TestGitCli_Clone_ReturnsRepoPath(t *testing.T) {
 		return stdout, stderr, 0, nil
 	}
 
-	repoPath, err := gitCli.Clone("https://github.com/test/repo.git", "main", 0)
+	repoPath, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "https://github.com/test/repo.git", Branch: "main"})
 
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(repoPath).To(ContainSubstring("my-custom-repo"))
@@ -117,7 +116,7 @@ func TestGitCli_Clone_ComplexRepoNames(t *testing.T) {
 		return stdout, stderr, 0, nil
 	}
 
-	repoPath, err := gitCli.Clone("https://github.com/test/repo.git", "main", 0)
+	repoPath, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "https://github.com/test/repo.git", Branch: "main"})
 
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(repoPath).To(ContainSubstring("repo-with-dashes_and_underscores.git"))
@@ -134,7 +133,7 @@ func TestGitCli_Clone_WithAdditionalHeaders(t *testing.T) {
 		return stdout, stderr, 0, nil
 	}
 
-	repoPath, err := gitCli.Clone("https://github.com/test/repo.git", "main", 0)
+	repoPath, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "https://github.com/test/repo.git", Branch: "main"})
 
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(repoPath).To(ContainSubstring("test-repo"))
@@ -149,7 +148,7 @@ func TestGitCli_Clone_FailsOnGitError(t *testing.T) {
 		return stdout, stderr, 0, errors.New("exit status 128")
 	}
 
-	_, err := gitCli.Clone("https://github.com/test/nonexistent.git", "main", 0)
+	_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "https://github.com/test/nonexistent.git", Branch: "main"})
 
 	g.Expect(err).To(HaveOccurred())
 	g.Expect(err.Error()).To(ContainSubstring("git clone failed"))
@@ -159,7 +158,7 @@ func TestGitCli_Clone_FailsOnEmptyURL(t *testing.T) {
 	g := NewWithT(t)
 	gitCli, _ := setupGitCli()
 
-	_, err := gitCli.Clone("", "main", 0)
+	_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "", Branch: "main"})
 	g.Expect(err).To(HaveOccurred())
 	g.Expect(err.Error()).To(ContainSubstring("url must be set to clone"))
 }
@@ -173,7 +172,7 @@ func TestGitCli_Clone_FailsOnUnparseableOutput(t *testing.T) {
 		return stdout, stderr, 0, nil
 	}
 
-	_, err := gitCli.Clone("https://github.com/test/repo.git", "main", 0)
+	_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "https://github.com/test/repo.git", Branch: "main"})
 
 	g.Expect(err).To(HaveOccurred())
 	g.Expect(err.Error()).To(ContainSubstring("failed to obtain cloned repository directory"))
@@ -245,3 +244,789 @@ func TestGitCli_GetRepoHeadFullSha_FailsOnGitError(t *testing.T) {
 	g.Expect(err).To(HaveOccurred())
 	g.Expect(err.Error()).To(ContainSubstring("git rev-parse failed"))
 }
+
+func TestGitCli_SetEnv(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	gitCli.SetEnv("GIT_SSH_COMMAND", "ssh -i key1")
+	gitCli.SetEnv("GIT_TERMINAL_PROMPT", "0")
+	gitCli.SetEnv("GIT_SSH_COMMAND", "ssh -i key2")
+	g.Expect(gitCli.Env).To(Equal([]string{"GIT_SSH_COMMAND=ssh -i key2", "GIT_TERMINAL_PROMPT=0"}))
+
+	executor.executeFunc = func(command string, args ...string) (stdout, stderr string, code int, err error) {
+		stderr = "Cloning into 'test-repo'...\n"
+		return stdout, stderr, 0, nil
+	}
+
+	_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "git@github.com:test/repo.git", Branch: "main"})
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(executor.env).To(ContainElement("GIT_SSH_COMMAND=ssh -i key2"))
+}
+
+func TestGitCli_Init(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal(""))
+		g.Expect(command).To(Equal("git"))
+		g.Expect(args).To(Equal([]string{"init", "repo"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.Init("repo")
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_Init_FailsOnEmptyDir(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, _ := setupGitCli()
+
+	err := gitCli.Init("")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestGitCli_AddRemote(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal("repo"))
+		g.Expect(args).To(Equal([]string{"remote", "add", "--", "origin", "https://github.com/test/repo.git"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.AddRemote("repo", "origin", "https://github.com/test/repo.git")
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_Fetch(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal("repo"))
+		g.Expect(args).To(Equal([]string{"fetch", "--depth", "1", "--", "origin", "refs/pull/123/head"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.Fetch(&cliwrappers.GitFetchArgs{
+		RepoDir:  "repo",
+		Remote:   "origin",
+		Refspecs: []string{"refs/pull/123/head"},
+		Depth:    1,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_Fetch_NoDepthWhenZero(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"fetch", "--", "origin", "v1.0.0"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.Fetch(&cliwrappers.GitFetchArgs{RepoDir: "repo", Remote: "origin", Refspecs: []string{"v1.0.0"}})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_Fetch_FailsOnGitError(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		stderr = "fatal: couldn't find remote ref"
+		return stdout, stderr, 128, errors.New("exit status 128")
+	}
+
+	err := gitCli.Fetch(&cliwrappers.GitFetchArgs{RepoDir: "repo", Remote: "origin", Refspecs: []string{"unknown"}})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("git fetch failed"))
+}
+
+func TestGitCli_Checkout(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal("repo"))
+		g.Expect(args).To(Equal([]string{"checkout", "FETCH_HEAD"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.Checkout("repo", "FETCH_HEAD")
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_SubmoduleUpdate(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal("repo"))
+		g.Expect(args).To(Equal([]string{"submodule", "update", "--init", "--recursive", "--depth", "1", "--", "libs/a", "libs/b"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.SubmoduleUpdate(&cliwrappers.GitSubmoduleUpdateArgs{
+		RepoDir:   "repo",
+		Recursive: true,
+		Depth:     1,
+		Paths:     []string{"libs/a", "libs/b"},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_SubmoduleUpdate_TopLevel(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"submodule", "update", "--init"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.SubmoduleUpdate(&cliwrappers.GitSubmoduleUpdateArgs{RepoDir: "repo"})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_ListSubmodules(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal("repo"))
+		g.Expect(args[:4]).To(Equal([]string{"submodule", "foreach", "--quiet", "--recursive"}))
+		stdout = "libs/a\thttps://github.com/test/a.git\t1111111111111111111111111111111111111111\n"
+		stdout += "libs/a/nested\thttps://github.com/test/nested.git\t2222222222222222222222222222222222222222\n"
+		return stdout, stderr, 0, nil
+	}
+
+	submodules, err := gitCli.ListSubmodules("repo", true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(submodules).To(Equal([]cliwrappers.GitSubmodule{
+		{Path: "libs/a", Url: "https://github.com/test/a.git", Commit: "1111111111111111111111111111111111111111"},
+		{Path: "libs/a/nested", Url: "https://github.com/test/nested.git", Commit: "2222222222222222222222222222222222222222"},
+	}))
+}
+
+func TestGitCli_ListSubmodules_NoSubmodules(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).ToNot(ContainElement("--recursive"))
+		return "", stderr, 0, nil
+	}
+
+	submodules, err := gitCli.ListSubmodules("repo", false)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(submodules).To(BeEmpty())
+}
+
+func TestGitCli_ListSubmodules_FailsOnUnparseableOutput(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		return "unexpected output\n", stderr, 0, nil
+	}
+
+	_, err := gitCli.ListSubmodules("repo", false)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to parse submodule info"))
+}
+
+func TestGitCli_ListDeclaredSubmodules(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal("repo"))
+		g.Expect(args).To(Equal([]string{"config", "--file", ".gitmodules", "--get-regexp", `^submodule\..*\.(path|url)$`}))
+		stdout = "submodule.libs.a.path libs/a\n"
+		stdout += "submodule.libs.a.url ../a.git\n"
+		stdout += "submodule.b.path b dir\n"
+		stdout += "submodule.b.url https://github.com/test/b.git\n"
+		return stdout, stderr, 0, nil
+	}
+
+	submodules, err := gitCli.ListDeclaredSubmodules("repo")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(submodules).To(Equal([]cliwrappers.GitSubmodule{
+		{Path: "libs/a", Url: "../a.git"},
+		{Path: "b dir", Url: "https://github.com/test/b.git"},
+	}))
+}
+
+func TestGitCli_ListDeclaredSubmodules_NoSubmodules(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		return "", "", 1, errors.New("exit status 1")
+	}
+
+	submodules, err := gitCli.ListDeclaredSubmodules("repo")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(submodules).To(BeEmpty())
+}
+
+func TestGitCli_ListDeclaredSubmodules_FailsOnInvalidConfig(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		return "", "fatal: bad config line 1 in file .gitmodules", 128, errors.New("exit status 128")
+	}
+
+	_, err := gitCli.ListDeclaredSubmodules("repo")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("git config failed"))
+}
+
+func TestGitCli_LfsInstall(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal("repo"))
+		g.Expect(args).To(Equal([]string{"lfs", "install", "--local"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.LfsInstall("repo")
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_LfsPull(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal("repo"))
+		g.Expect(args).To(Equal([]string{"lfs", "pull", "--include", "models/*,fixtures", "--exclude", "models/big.bin"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.LfsPull(&cliwrappers.GitLfsPullArgs{
+		RepoDir: "repo",
+		Include: []string{"models/*", "fixtures"},
+		Exclude: []string{"models/big.bin"},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_LfsPull_FailsOnGitError(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"lfs", "pull"}))
+		stderr = "batch response: Repository or object not found"
+		return stdout, stderr, 2, errors.New("exit status 2")
+	}
+
+	err := gitCli.LfsPull(&cliwrappers.GitLfsPullArgs{RepoDir: "repo"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("git lfs failed"))
+}
+
+func TestGitCli_Clone_WithFilterAndSparse(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	var capturedArgs []string
+	executor.executeFunc = func(command string, args ...string) (stdout, stderr string, code int, err error) {
+		capturedArgs = args
+		stderr = "Cloning into 'test-repo'...\n"
+		return stdout, stderr, 0, nil
+	}
+
+	_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "https://github.com/test/repo.git", Filter: "blob:none", Sparse: true})
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(capturedArgs).To(ContainElements("--filter", "blob:none", "--sparse"))
+}
+
+func TestGitCli_Fetch_WithFilter(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"fetch", "--filter", "tree:0", "--", "origin", "main"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.Fetch(&cliwrappers.GitFetchArgs{RepoDir: "repo", Remote: "origin", Refspecs: []string{"main"}, Filter: "tree:0"})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_SparseCheckoutSet(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal("repo"))
+		g.Expect(args).To(Equal([]string{"sparse-checkout", "set", "--cone", "components/a", "shared"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.SparseCheckoutSet("repo", []string{"components/a", "shared"})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_SparseCheckoutSet_FailsOnEmptyPaths(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, _ := setupGitCli()
+
+	err := gitCli.SparseCheckoutSet("repo", nil)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestGitCli_AddConfig(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, _ := setupGitCli()
+
+	gitCli.AddConfig("credential.helper", "store --file=/tmp/creds")
+	gitCli.AddConfig("http.sslCAInfo", "/tmp/ca.crt")
+
+	g.Expect(gitCli.Env).To(ConsistOf(
+		"GIT_CONFIG_KEY_0=credential.helper",
+		"GIT_CONFIG_VALUE_0=store --file=/tmp/creds",
+		"GIT_CONFIG_KEY_1=http.sslCAInfo",
+		"GIT_CONFIG_VALUE_1=/tmp/ca.crt",
+		"GIT_CONFIG_COUNT=2",
+	))
+}
+
+func TestGitCli_GetCommitInfo(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal("repo"))
+		g.Expect(args).To(Equal([]string{"log", "-1", "--format=%an <%ae>%n%ct%n%s", "HEAD"}))
+		stdout = "John Doe <john@example.com>\n1700000000\nFix: handle empty input\n"
+		return stdout, stderr, 0, nil
+	}
+
+	commitInfo, err := gitCli.GetCommitInfo("repo", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(commitInfo.Author).To(Equal("John Doe <john@example.com>"))
+	g.Expect(commitInfo.CommitterDate.Unix()).To(Equal(int64(1700000000)))
+	g.Expect(commitInfo.CommitterDate.Location()).To(Equal(time.UTC))
+	g.Expect(commitInfo.Subject).To(Equal("Fix: handle empty input"))
+}
+
+func TestGitCli_GetCommitInfo_FailsOnUnparseableOutput(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		stdout = "John Doe <john@example.com>\nnot-a-timestamp\nsubject\n"
+		return stdout, stderr, 0, nil
+	}
+
+	_, err := gitCli.GetCommitInfo("repo", "HEAD")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to parse commit timestamp"))
+}
+
+func TestGitCli_Describe(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"describe", "--tags", "--always"}))
+		return "v1.2.0-3-gabcdef1\n", stderr, 0, nil
+	}
+
+	describe, err := gitCli.Describe("repo")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(describe).To(Equal("v1.2.0-3-gabcdef1"))
+}
+
+func TestGitCli_CreateBundle(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal("repo"))
+		g.Expect(args).To(Equal([]string{"-c", "pack.threads=1", "bundle", "create", "/archive/repo.bundle", "HEAD"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.CreateBundle("repo", "/archive/repo.bundle")
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_CreateBundle_Error(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		return "", "fatal: Refusing to create empty bundle.", 128, errors.New("exit status 128")
+	}
+
+	err := gitCli.CreateBundle("repo", "/archive/repo.bundle")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(HavePrefix("git bundle failed"))
+}
+
+func TestGitCli_GetRemoteDefaultBranch(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(BeEmpty())
+		g.Expect(args).To(Equal([]string{"ls-remote", "--symref", "--", "https://github.com/test/repo.git", "HEAD"}))
+		return "ref: refs/heads/develop\tHEAD\nabcdef1234567890abcdef1234567890abcdef12\tHEAD\n", stderr, 0, nil
+	}
+
+	branch, err := gitCli.GetRemoteDefaultBranch("https://github.com/test/repo.git")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(branch).To(Equal("develop"))
+}
+
+func TestGitCli_GetRemoteDefaultBranch_NoSymref(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		return "abcdef1234567890abcdef1234567890abcdef12\tHEAD\n", stderr, 0, nil
+	}
+
+	_, err := gitCli.GetRemoteDefaultBranch("https://github.com/test/repo.git")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("remote HEAD doesn't point to a branch"))
+}
+
+func TestGitCli_Merge(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal("repo"))
+		g.Expect(args).To(Equal([]string{
+			"-c", "user.name=CI", "-c", "user.email=ci@example.com",
+			"merge", "--no-edit", "--no-ff", "-m", "Merge main", "FETCH_HEAD",
+		}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.Merge(&cliwrappers.GitMergeArgs{
+		RepoDir:   "repo",
+		Ref:       "FETCH_HEAD",
+		Message:   "Merge main",
+		UserName:  "CI",
+		UserEmail: "ci@example.com",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_Merge_FailsOnConflicts(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		if args[0] == "diff" {
+			g.Expect(args).To(Equal([]string{"diff", "--name-only", "--diff-filter=U"}))
+			return "README.md\nsrc/main file.go\n", stderr, 0, nil
+		}
+		stdout = "CONFLICT (content): Merge conflict in README.md"
+		return stdout, stderr, 1, errors.New("exit status 1")
+	}
+
+	err := gitCli.Merge(&cliwrappers.GitMergeArgs{RepoDir: "repo", Ref: "FETCH_HEAD"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(Equal("git merge failed due to conflicts in: README.md, src/main file.go"))
+}
+
+func TestGitCli_Merge_FailsOnGitError(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		if args[0] == "diff" {
+			return "", stderr, 0, nil
+		}
+		stderr = "fatal: refusing to merge unrelated histories"
+		return stdout, stderr, 128, errors.New("exit status 128")
+	}
+
+	err := gitCli.Merge(&cliwrappers.GitMergeArgs{RepoDir: "repo", Ref: "FETCH_HEAD"})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("git merge failed: exit status 128"))
+}
+
+func TestGitCli_Fetch_Unshallow(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"fetch", "--unshallow", "--", "origin"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.Fetch(&cliwrappers.GitFetchArgs{RepoDir: "repo", Remote: "origin", Unshallow: true})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_Clone_WithDirectory(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	var capturedArgs []string
+	executor.executeFunc = func(command string, args ...string) (stdout, stderr string, code int, err error) {
+		capturedArgs = args
+		stderr = "Cloning into 'source'...\n"
+		return stdout, stderr, 0, nil
+	}
+
+	repoPath, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "https://github.com/test/repo.git", Branch: "main", Directory: "source"})
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(repoPath).To(Equal("source"))
+	g.Expect(capturedArgs[len(capturedArgs)-3:]).To(Equal([]string{"--", "https://github.com/test/repo.git", "source"}))
+}
+
+func TestGitCli_GetRemoteUrl(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal("repo"))
+		g.Expect(args).To(Equal([]string{"remote", "get-url", "origin"}))
+		return "https://github.com/test/repo.git\n", stderr, 0, nil
+	}
+
+	url, err := gitCli.GetRemoteUrl("repo", "origin")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(url).To(Equal("https://github.com/test/repo.git"))
+}
+
+func TestGitCli_ResetHard(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"reset", "--hard", "FETCH_HEAD"}))
+		return stdout, stderr, 0, nil
+	}
+
+	g.Expect(gitCli.ResetHard("repo", "FETCH_HEAD")).To(Succeed())
+}
+
+func TestGitCli_Clean(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"clean", "-ffdx"}))
+		return stdout, "fatal: not a git repository", 128, errors.New("exit status 128")
+	}
+
+	err := gitCli.Clean("repo")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("git clean failed"))
+}
+
+func TestGitCli_GetCommitSignature(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"log", "-1", "--format=%G?%n%GS%n%GF", "HEAD"}))
+		return "G\nAlice <alice@example.com>\n5F8D794A06722AA9638E3BF995C550B325935D8F\n", stderr, 0, nil
+	}
+
+	signature, err := gitCli.GetCommitSignature("repo", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(signature.Status).To(Equal(cliwrappers.GitSignatureStatusGood))
+	g.Expect(signature.Signer).To(Equal("Alice <alice@example.com>"))
+	g.Expect(signature.Fingerprint).To(Equal("5F8D794A06722AA9638E3BF995C550B325935D8F"))
+}
+
+func TestGitCli_GetCommitSignature_NotSigned(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		return "N\n\n\n", stderr, 0, nil
+	}
+
+	signature, err := gitCli.GetCommitSignature("repo", "HEAD")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(signature.Status).To(Equal("N"))
+	g.Expect(signature.Signer).To(BeEmpty())
+}
+
+func TestGitCli_Clone_Mirror(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	var capturedArgs []string
+	executor.executeFunc = func(command string, args ...string) (stdout, stderr string, code int, err error) {
+		capturedArgs = args
+		return stdout, stderr, 0, nil
+	}
+
+	repoPath, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "https://github.com/test/repo.git", Branch: "main", Depth: 1, Directory: "mirror", Mirror: true})
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(repoPath).To(Equal("mirror"))
+	g.Expect(capturedArgs).To(Equal([]string{"clone", "--mirror", "--", "https://github.com/test/repo.git", "mirror"}))
+}
+
+func TestGitCli_Clone_WithReference(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	var capturedArgs []string
+	executor.executeFunc = func(command string, args ...string) (stdout, stderr string, code int, err error) {
+		capturedArgs = args
+		stderr = "Cloning into 'repo'...\n"
+		return stdout, stderr, 0, nil
+	}
+
+	_, err := gitCli.Clone(&cliwrappers.GitCloneArgs{Url: "https://github.com/test/repo.git", Branch: "main", Reference: "/cache/repo.git", Dissociate: true})
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(strings.Join(capturedArgs, " ")).To(ContainSubstring("--reference-if-able /cache/repo.git --dissociate"))
+}
+
+func TestGitCli_Fetch_Prune(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(workdir).To(Equal("mirror"))
+		g.Expect(args).To(Equal([]string{"fetch", "--prune", "--", "origin"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.Fetch(&cliwrappers.GitFetchArgs{RepoDir: "mirror", Remote: "origin", Prune: true})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_Repack(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"repack", "-a", "-d", "-q"}))
+		return stdout, stderr, 0, nil
+	}
+
+	g.Expect(gitCli.Repack("repo")).To(Succeed())
+}
+
+func TestGitCli_IsBareRepository(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"rev-parse", "--is-bare-repository"}))
+		return "true\n", stderr, 0, nil
+	}
+
+	isBare, err := gitCli.IsBareRepository("mirror")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(isBare).To(BeTrue())
+}
+
+func TestGitCli_Fetch_Deepen(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"fetch", "--deepen", "50", "--", "origin", "+main:refs/konflux/base"}))
+		return stdout, stderr, 0, nil
+	}
+
+	err := gitCli.Fetch(&cliwrappers.GitFetchArgs{RepoDir: "repo", Remote: "origin", Refspecs: []string{"+main:refs/konflux/base"}, Deepen: 50})
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestGitCli_IsShallowRepository(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"rev-parse", "--is-shallow-repository"}))
+		return "false\n", stderr, 0, nil
+	}
+
+	isShallow, err := gitCli.IsShallowRepository("repo")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(isShallow).To(BeFalse())
+}
+
+func TestGitCli_MergeBase(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"merge-base", "HEAD", "refs/konflux/base"}))
+		return "fbb188ba71af081cf64318649dfad24d0a82b131\n", stderr, 0, nil
+	}
+
+	mergeBase, err := gitCli.MergeBase("repo", "HEAD", "refs/konflux/base")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mergeBase).To(Equal("fbb188ba71af081cf64318649dfad24d0a82b131"))
+}
+
+func TestGitCli_MergeBase_NoCommonAncestor(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		return stdout, stderr, 1, errors.New("exit status 1")
+	}
+
+	mergeBase, err := gitCli.MergeBase("repo", "HEAD", "refs/konflux/base")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mergeBase).To(BeEmpty())
+}
+
+func TestGitCli_MergeBase_Error(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		return stdout, "fatal: Not a valid object name refs/konflux/base", 128, errors.New("exit status 128")
+	}
+
+	_, err := gitCli.MergeBase("repo", "HEAD", "refs/konflux/base")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("git merge-base failed"))
+}
+
+func TestGitCli_DiffNames(t *testing.T) {
+	g := NewWithT(t)
+	gitCli, executor := setupGitCli()
+
+	executor.executeInDirFunc = func(workdir, command string, args ...string) (stdout, stderr string, code int, err error) {
+		g.Expect(args).To(Equal([]string{"diff", "--name-only", "--no-renames", "-z", "base", "HEAD"}))
+		return "svc/api/main.go\x00docs/file with spaces.md\x00", stderr, 0, nil
+	}
+
+	paths, err := gitCli.DiffNames("repo", "base", "HEAD")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(paths).To(Equal([]string{"svc/api/main.go", "docs/file with spaces.md"}))
+}
+
+func TestFullShaRegex(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(cliwrappers.FullShaRegex.MatchString(strings.Repeat("a", 40))).To(BeTrue())
+	g.Expect(cliwrappers.FullShaRegex.MatchString(strings.Repeat("a", 64))).To(BeTrue())
+	g.Expect(cliwrappers.FullShaRegex.MatchString(strings.Repeat("a", 41))).To(BeFalse())
+	g.Expect(cliwrappers.FullShaRegex.MatchString(strings.Repeat("A", 40))).To(BeFalse())
+	g.Expect(cliwrappers.FullShaRegex.MatchString("main")).To(BeFalse())
+}
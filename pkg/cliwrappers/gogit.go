@@ -0,0 +1,615 @@
+package cliwrappers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage/memory"
+
+	"github.com/mmorhun/konflux-task-cli/pkg/common"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+// ErrGoGitUnsupported is returned by operations that go-git backend cannot perform.
+var ErrGoGitUnsupported = errors.New("not supported by go-git backend, git CLI is required")
+
+// goGitHarmlessEnv lists environment variables that may be ignored by go-git backend,
+// because it never prompts and doesn't support LFS.
+var goGitHarmlessEnv = map[string]bool{
+	"GIT_TERMINAL_PROMPT": true,
+	"GIT_LFS_SKIP_SMUDGE": true,
+}
+
+// goGitHarmlessConfig lists git config options that may be ignored by go-git backend,
+// because it doesn't check repository ownership.
+var goGitHarmlessConfig = map[string]bool{
+	"safe.directory": true,
+}
+
+var _ GitCliInterface = &GoGit{}
+
+// GoGit implements GitCliInterface using pure Go git library.
+// It's used when git CLI is not available, so only basic operations are supported.
+type GoGit struct {
+	Verbose bool
+
+	// unsupportedSettings holds names of environment variables and config options
+	// which were requested, but cannot be honored by go-git.
+	unsupportedSettings []string
+}
+
+func NewGoGit(verbose bool) *GoGit {
+	return &GoGit{Verbose: verbose}
+}
+
+func unsupported(operation string) error {
+	return fmt.Errorf("%s is %w", operation, ErrGoGitUnsupported)
+}
+
+func (g *GoGit) progress() io.Writer {
+	if g.Verbose {
+		return os.Stdout
+	}
+	return nil
+}
+
+// checkSettings fails if any git configuration that go-git cannot honor was requested,
+// e.g. credentials helper or ssh command.
+func (g *GoGit) checkSettings() error {
+	if len(g.unsupportedSettings) > 0 {
+		return fmt.Errorf("%s: %w", strings.Join(g.unsupportedSettings, ", "), ErrGoGitUnsupported)
+	}
+	return nil
+}
+
+// getRepoDirFromUrl returns directory name git derives from the repository url.
+func getRepoDirFromUrl(url string) string {
+	url = strings.TrimRight(url, "/")
+	url = strings.TrimSuffix(url, ".git")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+	return url
+}
+
+func (g *GoGit) Clone(args *GitCloneArgs) (string, error) {
+	if args.Url == "" {
+		return "", errors.New("url must be set to clone")
+	}
+	if err := g.checkSettings(); err != nil {
+		return "", err
+	}
+	if args.Filter != "" {
+		return "", unsupported("partial clone")
+	}
+	if args.Sparse {
+		return "", unsupported("sparse checkout")
+	}
+	if args.Reference != "" {
+		l.Logger.Warnf("Reference repository '%s' is ignored by go-git backend", args.Reference)
+	}
+
+	repoDir := args.Directory
+	if repoDir == "" {
+		repoDir = getRepoDirFromUrl(args.Url)
+	}
+
+	cloneOptions := &git.CloneOptions{
+		URL:      args.Url,
+		Progress: g.progress(),
+	}
+	if args.Mirror {
+		cloneOptions.Mirror = true
+		if _, err := git.PlainCloneContext(context.Background(), repoDir, true, cloneOptions); err != nil {
+			return "", fmt.Errorf("git clone failed: %w", err)
+		}
+		return repoDir, nil
+	}
+
+	cloneOptions.SingleBranch = true
+	cloneOptions.Depth = args.Depth
+
+	if args.Branch == "" {
+		// Without reference name, go-git checks out the remote HEAD.
+		if _, err := git.PlainCloneContext(context.Background(), repoDir, false, cloneOptions); err != nil {
+			return "", fmt.Errorf("git clone failed: %w", err)
+		}
+		return repoDir, nil
+	}
+
+	// git clone --branch accepts tags as well
+	var err error
+	for _, refName := range []plumbing.ReferenceName{plumbing.NewBranchReferenceName(args.Branch), plumbing.NewTagReferenceName(args.Branch)} {
+		cloneOptions.ReferenceName = refName
+		_, err = git.PlainCloneContext(context.Background(), repoDir, false, cloneOptions)
+		if !errors.Is(err, plumbing.ErrReferenceNotFound) && !isNoMatchingRefSpecError(err) {
+			break
+		}
+		if removeErr := common.RemoveDirContent(repoDir); removeErr != nil {
+			return "", removeErr
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("git clone failed: %w", err)
+	}
+	return repoDir, nil
+}
+
+func isNoMatchingRefSpecError(err error) bool {
+	var noMatchingRefSpecError git.NoMatchingRefSpecError
+	return errors.As(err, &noMatchingRefSpecError)
+}
+
+func (g *GoGit) GetRepoHeadFullSha(gitRepoDir string) (string, error) {
+	repo, err := git.PlainOpen(gitRepoDir)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	return head.Hash().String(), nil
+}
+
+func (g *GoGit) Init(repoDir string) error {
+	initOptions := &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.Main},
+	}
+	_, err := git.PlainInitWithOptions(repoDir, initOptions)
+	if errors.Is(err, git.ErrRepositoryAlreadyExists) {
+		return nil
+	}
+	return err
+}
+
+func (g *GoGit) AddRemote(repoDir, name, url string) error {
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return err
+	}
+	_, err = repo.CreateRemote(&config.RemoteConfig{
+		Name: name,
+		URLs: []string{url},
+	})
+	return err
+}
+
+// Fetch fetches given refspecs like git CLI does and points FETCH_HEAD to the first fetched commit.
+// Refspecs without destination are fetched into FETCH_HEAD only.
+func (g *GoGit) Fetch(args *GitFetchArgs) error {
+	if args.Remote == "" {
+		return errors.New("remote to fetch from must be set")
+	}
+	if err := g.checkSettings(); err != nil {
+		return err
+	}
+	if args.Filter != "" {
+		return unsupported("partial clone")
+	}
+	if args.Unshallow || args.Deepen != 0 {
+		return unsupported("deepening of shallow history")
+	}
+
+	repo, err := git.PlainOpen(args.RepoDir)
+	if err != nil {
+		return err
+	}
+	remote, err := repo.Remote(args.Remote)
+	if err != nil {
+		return err
+	}
+
+	var refSpecs []config.RefSpec
+	var fetchHeadRef plumbing.ReferenceName
+	if len(args.Refspecs) == 0 {
+		refSpecs = remote.Config().Fetch
+	} else {
+		remoteRefs, err := remote.List(&git.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("git fetch failed: %w", err)
+		}
+		for i, refspec := range args.Refspecs {
+			force := strings.HasPrefix(refspec, "+")
+			src, dst, _ := strings.Cut(strings.TrimPrefix(refspec, "+"), ":")
+			src, err := resolveRemoteRefName(src, remoteRefs)
+			if err != nil {
+				return err
+			}
+			if dst == "" {
+				// Keep fetched commit reachable until FETCH_HEAD is set
+				dst = fmt.Sprintf("refs/fetch-head/%d", i)
+				force = true
+			}
+			if i == 0 {
+				fetchHeadRef = plumbing.ReferenceName(dst)
+			}
+			refSpec := config.RefSpec(src + ":" + dst)
+			if force {
+				refSpec = "+" + refSpec
+			}
+			refSpecs = append(refSpecs, refSpec)
+		}
+	}
+
+	err = repo.FetchContext(context.Background(), &git.FetchOptions{
+		RemoteName: args.Remote,
+		RefSpecs:   refSpecs,
+		Depth:      args.Depth,
+		Prune:      args.Prune,
+		Tags:       git.NoTags,
+		Force:      true,
+		Progress:   g.progress(),
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("git fetch failed: %w", err)
+	}
+
+	if fetchHeadRef != "" {
+		ref, err := repo.Reference(fetchHeadRef, true)
+		if err != nil {
+			return err
+		}
+		if err := repo.Storer.SetReference(plumbing.NewHashReference("FETCH_HEAD", ref.Hash())); err != nil {
+			return err
+		}
+		if strings.HasPrefix(fetchHeadRef.String(), "refs/fetch-head/") {
+			for i := range args.Refspecs {
+				_ = repo.Storer.RemoveReference(plumbing.ReferenceName(fmt.Sprintf("refs/fetch-head/%d", i)))
+			}
+		}
+	}
+	return nil
+}
+
+// resolveRemoteRefName expands short ref name to the full remote ref name, like git CLI does.
+func resolveRemoteRefName(name string, remoteRefs []*plumbing.Reference) (string, error) {
+	if FullShaRegex.MatchString(name) || strings.HasPrefix(name, "refs/") {
+		return name, nil
+	}
+	for _, candidate := range []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName(name),
+		plumbing.NewTagReferenceName(name),
+	} {
+		for _, ref := range remoteRefs {
+			if ref.Name() == candidate {
+				return candidate.String(), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("couldn't find remote ref %s", name)
+}
+
+func (g *GoGit) resolveCommit(repo *git.Repository, ref string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve '%s': %w", ref, err)
+	}
+	return repo.CommitObject(*hash)
+}
+
+func (g *GoGit) Checkout(repoDir, ref string) error {
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return err
+	}
+	commit, err := g.resolveCommit(repo, ref)
+	if err != nil {
+		return err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: commit.Hash}); err != nil {
+		return fmt.Errorf("git checkout failed: %w", err)
+	}
+	return nil
+}
+
+func (g *GoGit) GetRemoteUrl(repoDir, remoteName string) (string, error) {
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return "", err
+	}
+	remote, err := repo.Remote(remoteName)
+	if err != nil {
+		return "", err
+	}
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return "", fmt.Errorf("remote '%s' has no url", remoteName)
+	}
+	return urls[0], nil
+}
+
+func (g *GoGit) GetRemoteDefaultBranch(url string) (string, error) {
+	if err := g.checkSettings(); err != nil {
+		return "", err
+	}
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{url},
+	})
+	refs, err := remote.ListContext(context.Background(), &git.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list remote references: %w", err)
+	}
+	for _, ref := range refs {
+		if ref.Name() == plumbing.HEAD && ref.Type() == plumbing.SymbolicReference && ref.Target().IsBranch() {
+			return ref.Target().Short(), nil
+		}
+	}
+	return "", errors.New("remote HEAD doesn't point to a branch")
+}
+
+func (g *GoGit) ResetHard(repoDir, ref string) error {
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return err
+	}
+	commit, err := g.resolveCommit(repo, ref)
+	if err != nil {
+		return err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if err := worktree.Reset(&git.ResetOptions{Commit: commit.Hash, Mode: git.HardReset}); err != nil {
+		return fmt.Errorf("git reset failed: %w", err)
+	}
+	return nil
+}
+
+// Clean removes untracked files and directories. Unlike git CLI backend, ignored files are kept.
+func (g *GoGit) Clean(repoDir string) error {
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return worktree.Clean(&git.CleanOptions{Dir: true})
+}
+
+func (g *GoGit) Repack(repoDir string) error {
+	return unsupported("repack")
+}
+
+func (g *GoGit) IsBareRepository(repoDir string) (bool, error) {
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return false, err
+	}
+	repoConfig, err := repo.Config()
+	if err != nil {
+		return false, err
+	}
+	return repoConfig.Core.IsBare, nil
+}
+
+func (g *GoGit) IsShallowRepository(repoDir string) (bool, error) {
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return false, err
+	}
+	shallowCommits, err := repo.Storer.Shallow()
+	if err != nil {
+		return false, err
+	}
+	return len(shallowCommits) > 0, nil
+}
+
+func (g *GoGit) MergeBase(repoDir, ref1, ref2 string) (string, error) {
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return "", err
+	}
+	commit1, err := g.resolveCommit(repo, ref1)
+	if err != nil {
+		return "", err
+	}
+	commit2, err := g.resolveCommit(repo, ref2)
+	if err != nil {
+		return "", err
+	}
+	mergeBases, err := commit1.MergeBase(commit2)
+	if err != nil {
+		// Shallow history is cut, so the common ancestor is not available.
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	if len(mergeBases) == 0 {
+		return "", nil
+	}
+	return mergeBases[0].Hash.String(), nil
+}
+
+func (g *GoGit) DiffNames(repoDir, fromRef, toRef string) ([]string, error) {
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	var trees []*object.Tree
+	for _, ref := range []string{fromRef, toRef} {
+		commit, err := g.resolveCommit(repo, ref)
+		if err != nil {
+			return nil, err
+		}
+		tree, err := commit.Tree()
+		if err != nil {
+			return nil, err
+		}
+		trees = append(trees, tree)
+	}
+	changes, err := object.DiffTree(trees[0], trees[1])
+	if err != nil {
+		return nil, err
+	}
+
+	pathsSet := make(map[string]bool)
+	for _, change := range changes {
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			if name != "" {
+				pathsSet[name] = true
+			}
+		}
+	}
+	paths := make([]string, 0, len(pathsSet))
+	for path := range pathsSet {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func (g *GoGit) SparseCheckoutSet(repoDir string, paths []string) error {
+	return unsupported("sparse checkout")
+}
+
+func (g *GoGit) Merge(args *GitMergeArgs) error {
+	return unsupported("merge")
+}
+
+func (g *GoGit) SubmoduleUpdate(args *GitSubmoduleUpdateArgs) error {
+	return unsupported("submodules update")
+}
+
+func (g *GoGit) ListSubmodules(repoDir string, recursive bool) ([]GitSubmodule, error) {
+	return nil, unsupported("submodules listing")
+}
+
+func (g *GoGit) ListDeclaredSubmodules(repoDir string) ([]GitSubmodule, error) {
+	return nil, unsupported("submodules listing")
+}
+
+func (g *GoGit) GetCommitInfo(repoDir, ref string) (*GitCommitInfo, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := g.resolveCommit(repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	subject, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+	return &GitCommitInfo{
+		Author:        fmt.Sprintf("%s <%s>", commit.Author.Name, commit.Author.Email),
+		CommitterDate: commit.Committer.When.UTC(),
+		Subject:       subject,
+	}, nil
+}
+
+// Describe returns the nearest tag reachable from HEAD with the commit distance suffix, if any.
+// Falls back to abbreviated commit SHA if no tags found.
+func (g *GoGit) Describe(repoDir string) (string, error) {
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	tagsByCommit := make(map[plumbing.Hash]string)
+	tags, err := repo.Tags()
+	if err != nil {
+		return "", err
+	}
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		hash := ref.Hash()
+		// Peel annotated tags
+		if tag, err := repo.TagObject(hash); err == nil {
+			hash = tag.Target
+		}
+		tagsByCommit[hash] = ref.Name().Short()
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	shortSha := head.Hash().String()[:7]
+	commits, err := repo.Log(&git.LogOptions{From: head.Hash(), Order: git.LogOrderBSF})
+	if err != nil {
+		return "", err
+	}
+	distance := 0
+	describe := ""
+	err = commits.ForEach(func(commit *object.Commit) error {
+		if tagName, isTagged := tagsByCommit[commit.Hash]; isTagged {
+			if distance == 0 {
+				describe = tagName
+			} else {
+				describe = fmt.Sprintf("%s-%d-g%s", tagName, distance, shortSha)
+			}
+			return storer.ErrStop
+		}
+		distance++
+		return nil
+	})
+	// Shallow history is cut
+	if err != nil && !errors.Is(err, plumbing.ErrObjectNotFound) {
+		return "", err
+	}
+	if describe == "" {
+		return shortSha, nil
+	}
+	return describe, nil
+}
+
+func (g *GoGit) CreateBundle(repoDir, bundlePath string) error {
+	return unsupported("git bundle")
+}
+
+func (g *GoGit) GetCommitSignature(repoDir, ref string) (*GitCommitSignature, error) {
+	return nil, unsupported("signature verification")
+}
+
+func (g *GoGit) IsLfsAvailable() bool {
+	return false
+}
+
+func (g *GoGit) LfsInstall(repoDir string) error {
+	return unsupported("git lfs")
+}
+
+func (g *GoGit) LfsPull(args *GitLfsPullArgs) error {
+	return unsupported("git lfs")
+}
+
+// SetEnv records the environment variable. go-git doesn't use git environment variables,
+// so any of them, except harmless ones, makes network operations fail.
+func (g *GoGit) SetEnv(name, value string) {
+	if !goGitHarmlessEnv[name] {
+		g.unsupportedSettings = append(g.unsupportedSettings, name+" environment variable")
+	}
+}
+
+// AddConfig records the config option. go-git doesn't support options passed to git CLI,
+// so any of them, except harmless ones, makes network operations fail.
+func (g *GoGit) AddConfig(key, value string) {
+	if goGitHarmlessConfig[key] {
+		return
+	}
+	g.unsupportedSettings = append(g.unsupportedSettings, key+" config")
+}
@@ -0,0 +1,57 @@
+package cliwrappers
+
+import (
+	"errors"
+	"fmt"
+
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+type GpgCliInterface interface {
+	ImportKeys(homeDir, keyringPath string) error
+}
+
+var _ GpgCliInterface = &GpgCli{}
+
+type GpgCli struct {
+	Executor CliExecutorInterface
+	Verbose  bool
+}
+
+func NewGpgCli(executor CliExecutorInterface, verbose bool) (*GpgCli, error) {
+	gpgCliAvailable, err := CheckCliToolAvailable("gpg")
+	if err != nil {
+		return nil, err
+	}
+	if !gpgCliAvailable {
+		return nil, errors.New("gpg CLI is not available")
+	}
+
+	return &GpgCli{
+		Executor: executor,
+		Verbose:  verbose,
+	}, nil
+}
+
+// ImportKeys imports public keys from the given keyring file, armored or binary, into the given gpg home directory.
+func (g *GpgCli) ImportKeys(homeDir, keyringPath string) error {
+	if homeDir == "" {
+		return errors.New("gpg home directory must be set")
+	}
+	if keyringPath == "" {
+		return errors.New("keyring file must be set")
+	}
+
+	stdout, stderr, _, err := g.Executor.Execute("gpg", "--homedir", homeDir, "--batch", "--import", keyringPath)
+	if err != nil {
+		l.Logger.Errorf("[stdout]:\n%s", stdout)
+		l.Logger.Errorf("[stderr]:\n%s", stderr)
+		return fmt.Errorf("gpg import failed: %v", err)
+	}
+
+	if g.Verbose {
+		l.Logger.Info("[stderr]:\n" + stderr)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package cliwrappers
+
+// NetworkConfig holds proxy and TLS settings for executed commands.
+type NetworkConfig struct {
+	HttpProxy  string
+	HttpsProxy string
+	// NoProxy is a comma separated list of hosts to access directly.
+	NoProxy string
+	// CaBundle is a path to PEM file with all trusted certificate authorities.
+	// It replaces the system trust store.
+	CaBundle string
+}
+
+func (n *NetworkConfig) IsEmpty() bool {
+	return n.HttpProxy == "" && n.HttpsProxy == "" && n.NoProxy == "" && n.CaBundle == ""
+}
+
+// Env returns environment variables that apply the settings to git, curl and Go based tools,
+// e.g. skopeo and buildah. Both upper and lower case proxy variables are set,
+// because different tools read different ones.
+func (n *NetworkConfig) Env() []string {
+	var env []string
+	if n.HttpProxy != "" {
+		env = append(env, "HTTP_PROXY="+n.HttpProxy, "http_proxy="+n.HttpProxy)
+	}
+	if n.HttpsProxy != "" {
+		env = append(env, "HTTPS_PROXY="+n.HttpsProxy, "https_proxy="+n.HttpsProxy)
+	}
+	if n.NoProxy != "" {
+		env = append(env, "NO_PROXY="+n.NoProxy, "no_proxy="+n.NoProxy)
+	}
+	if n.CaBundle != "" {
+		env = append(env,
+			"GIT_SSL_CAINFO="+n.CaBundle,
+			"CURL_CA_BUNDLE="+n.CaBundle,
+			"SSL_CERT_FILE="+n.CaBundle,
+		)
+	}
+	return env
+}
@@ -0,0 +1,40 @@
+package cliwrappers_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+)
+
+func TestNetworkConfig_Env(t *testing.T) {
+	g := NewWithT(t)
+
+	networkConfig := &cliwrappers.NetworkConfig{
+		HttpProxy:  "http://proxy.example.com:3128",
+		HttpsProxy: "http://proxy.example.com:3129",
+		NoProxy:    "localhost,.cluster.local",
+		CaBundle:   "/etc/pki/ca-bundle.pem",
+	}
+	g.Expect(networkConfig.IsEmpty()).To(BeFalse())
+	g.Expect(networkConfig.Env()).To(Equal([]string{
+		"HTTP_PROXY=http://proxy.example.com:3128",
+		"http_proxy=http://proxy.example.com:3128",
+		"HTTPS_PROXY=http://proxy.example.com:3129",
+		"https_proxy=http://proxy.example.com:3129",
+		"NO_PROXY=localhost,.cluster.local",
+		"no_proxy=localhost,.cluster.local",
+		"GIT_SSL_CAINFO=/etc/pki/ca-bundle.pem",
+		"CURL_CA_BUNDLE=/etc/pki/ca-bundle.pem",
+		"SSL_CERT_FILE=/etc/pki/ca-bundle.pem",
+	}))
+}
+
+func TestNetworkConfig_Env_Empty(t *testing.T) {
+	g := NewWithT(t)
+
+	networkConfig := &cliwrappers.NetworkConfig{}
+	g.Expect(networkConfig.IsEmpty()).To(BeTrue())
+	g.Expect(networkConfig.Env()).To(BeEmpty())
+}
@@ -38,6 +38,10 @@ var ApplyTagsParamsConfig = map[string]common.Parameter{
 		DefaultValue: "",
 		Usage:        "Tags to add to the given image",
 	},
+	"http-proxy":  httpProxyParam,
+	"https-proxy": httpsProxyParam,
+	"no-proxy":    noProxyParam,
+	"ca-bundle":   caBundleParam,
 	"verbose": {
 		Name:         "verbose",
 		ShortName:    "v",
@@ -49,6 +53,8 @@ var ApplyTagsParamsConfig = map[string]common.Parameter{
 }
 
 type ApplyTagsParams struct {
+	NetworkParams
+
 	ImageUrl string   `paramName:"image-url"`
 	Digest   string   `paramName:"digest"`
 	NewTags  []string `paramName:"tags"`
@@ -84,7 +90,7 @@ func NewApplyTags(cmd *cobra.Command) (*ApplyTags, error) {
 }
 
 func (c *ApplyTags) initCliWrappers() error {
-	executor := cliWrappers.NewCliExecutor(c.Params.Verbose)
+	executor := newNetworkExecutor(c.Params.networkConfig(), c.Params.Verbose)
 
 	skopeoCli, err := cliWrappers.NewSkopeoCli(executor, c.Params.Verbose)
 	if err != nil {
@@ -101,6 +107,7 @@ func (c *ApplyTags) Run() error {
 		if len(c.Params.NewTags) > 0 {
 			l.Logger.Infof("[param] Tags: %s", strings.Join(c.Params.NewTags, ", "))
 		}
+		logNetworkConfig(c.Params.networkConfig())
 	}
 
 	if err := c.validateParams(); err != nil {
@@ -183,7 +190,7 @@ func (c *ApplyTags) validateParams() error {
 		return fmt.Errorf("image digest '%s' is invalid", c.Params.Digest)
 	}
 
-	return nil
+	return validateNetworkConfig(c.Params.networkConfig())
 }
 
 func (c *ApplyTags) stripTag(imageURL string) string {
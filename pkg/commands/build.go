@@ -51,6 +51,68 @@ var ImageBuildParamsConfig = map[string]common.Parameter{
 		DefaultValue: "",
 		Usage:        "Annotations to add to the image",
 	},
+	"build-args": {
+		Name:         "build-args",
+		EnvVarName:   "BUILD_ARGS",
+		TypeKind:     reflect.Array,
+		DefaultValue: "",
+		Usage:        "Build arguments in KEY=VALUE form, override the ones from build args file",
+	},
+	"build-args-file": {
+		Name:       "build-args-file",
+		EnvVarName: "BUILD_ARGS_FILE",
+		TypeKind:   reflect.String,
+		Usage:      "Path to file with KEY=VALUE build arguments, one per line",
+	},
+	"commit-sha": {
+		Name:       "commit-sha",
+		EnvVarName: "COMMIT_SHA",
+		TypeKind:   reflect.String,
+		Usage:      "Commit of the sources, passed as COMMIT_SHA build argument",
+	},
+	"source-url": {
+		Name:       "source-url",
+		EnvVarName: "SOURCE_URL",
+		TypeKind:   reflect.String,
+		Usage:      "Url of the sources repository, passed as SOURCE_URL build argument",
+	},
+	"platforms": {
+		Name:         "platforms",
+		EnvVarName:   "PLATFORMS",
+		TypeKind:     reflect.Array,
+		DefaultValue: "",
+		Usage:        "Platforms to build for, e.g. linux/amd64,linux/arm64. Produces image index if set",
+	},
+	"secrets-dir": {
+		Name:       "secrets-dir",
+		EnvVarName: "SECRETS_DIR",
+		TypeKind:   reflect.String,
+		Usage:      "Directory with build secrets, each file is a secret with the file name as id",
+	},
+	"secrets": {
+		Name:         "secrets",
+		EnvVarName:   "BUILD_SECRETS",
+		TypeKind:     reflect.Array,
+		DefaultValue: "",
+		Usage:        "Build secrets in id=path form, override the ones from secrets dir",
+	},
+	"hermetic": {
+		Name:         "hermetic",
+		EnvVarName:   "HERMETIC",
+		TypeKind:     reflect.Bool,
+		DefaultValue: "false",
+		Usage:        "Disables network access of RUN instructions",
+	},
+	"prefetch-dir": {
+		Name:       "prefetch-dir",
+		EnvVarName: "PREFETCH_DIR",
+		TypeKind:   reflect.String,
+		Usage:      "Directory with prefetched dependencies to mount into the build at " + prefetchMountPath,
+	},
+	"http-proxy":  httpProxyParam,
+	"https-proxy": httpsProxyParam,
+	"no-proxy":    noProxyParam,
+	"ca-bundle":   caBundleParam,
 	"verbose": {
 		Name:         "verbose",
 		ShortName:    "v",
@@ -62,17 +124,32 @@ var ImageBuildParamsConfig = map[string]common.Parameter{
 }
 
 type ImageBuildParams struct {
+	NetworkParams
+
 	Image          string   `paramName:"image"`
 	DockerfilePath string   `paramName:"dockerfile"`
 	SourceDir      string   `paramName:"source-dir"`
 	Labels         []string `paramName:"labels"`
 	Annotations    []string `paramName:"annotations"`
+	BuildArgs      []string `paramName:"build-args"`
+	BuildArgsFile  string   `paramName:"build-args-file"`
+	CommitSha      string   `paramName:"commit-sha"`
+	SourceUrl      string   `paramName:"source-url"`
+	Platforms      []string `paramName:"platforms"`
+	SecretsDir     string   `paramName:"secrets-dir"`
+	Secrets        []string `paramName:"secrets"`
+	Hermetic       bool     `paramName:"hermetic"`
+	PrefetchDir    string   `paramName:"prefetch-dir"`
 	Verbose        bool     `paramName:"verbose"`
 }
 
 type ImageBuildResultFilesPath struct {
 	ImageUrl string `env:"RESULT_IMAGE_URL"`
 	Digest   string `env:"RESULT_IMAGE_DIGEST"`
+	// JSON map of platform to image digest for multi-platform builds
+	PlatformDigests string `env:"RESULT_PLATFORM_DIGESTS,optional"`
+	// Whether the build had no network access, for provenance
+	Hermetic string `env:"RESULT_HERMETIC,optional"`
 }
 
 type ImageBuildCliWrappers struct {
@@ -110,7 +187,7 @@ func NewImageBuild(cmd *cobra.Command) (*ImageBuild, error) {
 }
 
 func (c *ImageBuild) initCliWrappers() error {
-	executor := cliWrappers.NewCliExecutor(c.Params.Verbose)
+	executor := newNetworkExecutor(c.Params.networkConfig(), c.Params.Verbose)
 
 	buildahCli, err := cliWrappers.NewBuildahCli(executor, c.Params.Verbose)
 	if err != nil {
@@ -133,19 +210,76 @@ func (c *ImageBuild) Run() error {
 		if len(c.Params.Annotations) > 0 {
 			l.Logger.Infof("[param] Annotations: %s", strings.Join(c.Params.Annotations, ", "))
 		}
+		if len(c.Params.BuildArgs) > 0 {
+			l.Logger.Infof("[param] Build args: %s", strings.Join(c.Params.BuildArgs, ", "))
+		}
+		if c.Params.BuildArgsFile != "" {
+			l.Logger.Infof("[param] Build args file: %s", c.Params.BuildArgsFile)
+		}
+		if c.Params.CommitSha != "" {
+			l.Logger.Infof("[param] Commit SHA: %s", c.Params.CommitSha)
+		}
+		if c.Params.SourceUrl != "" {
+			l.Logger.Infof("[param] Source URL: %s", c.Params.SourceUrl)
+		}
+		if len(c.Params.Platforms) > 0 {
+			l.Logger.Infof("[param] Platforms: %s", strings.Join(c.Params.Platforms, ", "))
+		}
+		if c.Params.SecretsDir != "" {
+			l.Logger.Infof("[param] Secrets dir: %s", c.Params.SecretsDir)
+		}
+		if len(c.Params.Secrets) > 0 {
+			l.Logger.Infof("[param] Secrets: %s", strings.Join(c.Params.Secrets, ", "))
+		}
+		if c.Params.Hermetic {
+			l.Logger.Info("[param] Hermetic: true")
+		}
+		if c.Params.PrefetchDir != "" {
+			l.Logger.Infof("[param] Prefetch dir: %s", c.Params.PrefetchDir)
+		}
+		logNetworkConfig(c.Params.networkConfig())
 	}
 
 	if err := c.validateParams(); err != nil {
 		return err
 	}
 
+	imageBuildArgs, err := c.getBuildArgs()
+	if err != nil {
+		return err
+	}
+	secrets, err := c.getBuildSecrets()
+	if err != nil {
+		return err
+	}
+
 	buildArgs := &cliWrappers.BuildahBuildArgs{
 		Image:          c.Params.Image,
 		DockerfilePath: c.Params.DockerfilePath,
 		SourceDir:      c.Params.SourceDir,
 		Labels:         c.Params.Labels,
 		Annotations:    c.Params.Annotations,
+		BuildArgs:      imageBuildArgs,
+		Secrets:        secrets,
+	}
+	if c.Params.Hermetic {
+		buildArgs.Network = "none"
+	}
+	if c.Params.PrefetchDir != "" {
+		cleanup, err := c.applyPrefetchDir(buildArgs)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
 	}
+
+	if platforms := c.getPlatforms(); len(platforms) > 0 {
+		if err := c.buildPlatforms(buildArgs, platforms); err != nil {
+			return err
+		}
+		return c.writeHermeticResult()
+	}
+
 	image, _, err := c.CliWrappers.BuildahCli.Build(buildArgs)
 	if err != nil {
 		return err
@@ -162,6 +296,9 @@ func (c *ImageBuild) Run() error {
 	if err := c.ResultsWriter.WriteResultString(digest, c.Results.Digest); err != nil {
 		return err
 	}
+	if err := c.writeHermeticResult(); err != nil {
+		return err
+	}
 
 	if c.Params.Verbose {
 		l.Logger.Infof("[result] Image URL: %s", image)
@@ -172,5 +309,18 @@ func (c *ImageBuild) Run() error {
 }
 
 func (c *ImageBuild) validateParams() error {
-	return nil
+	for _, buildArg := range c.Params.BuildArgs {
+		if _, _, err := parseBuildArg(buildArg); err != nil {
+			return err
+		}
+	}
+	if err := validatePlatforms(c.getPlatforms()); err != nil {
+		return err
+	}
+	for _, secret := range c.Params.Secrets {
+		if _, _, err := parseBuildSecret(secret); err != nil {
+			return err
+		}
+	}
+	return validateNetworkConfig(c.Params.networkConfig())
 }
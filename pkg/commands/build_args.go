@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Well-known build arguments, which are passed to the build automatically when their values are known.
+const (
+	buildArgCommitSha = "COMMIT_SHA"
+	buildArgSourceUrl = "SOURCE_URL"
+)
+
+var buildArgNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseBuildArg splits KEY=VALUE build argument into its name and value.
+func parseBuildArg(buildArg string) (string, string, error) {
+	name, value, found := strings.Cut(buildArg, "=")
+	if !found {
+		return "", "", fmt.Errorf("build arg '%s' must be in KEY=VALUE form", buildArg)
+	}
+	if !buildArgNameRegex.MatchString(name) {
+		return "", "", fmt.Errorf("build arg name '%s' is invalid", name)
+	}
+	return name, value, nil
+}
+
+// readBuildArgsFile reads KEY=VALUE build arguments, one per line.
+// Empty lines and lines starting with # are skipped.
+func readBuildArgsFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build args file: %w", err)
+	}
+	defer file.Close()
+
+	var buildArgs []string
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, _, err := parseBuildArg(line); err != nil {
+			return nil, fmt.Errorf("build args file line %d: %w", lineNumber, err)
+		}
+		buildArgs = append(buildArgs, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read build args file: %w", err)
+	}
+	return buildArgs, nil
+}
+
+// getBuildArgs returns build arguments from the build args file, overridden by the build args param,
+// followed by the well-known build arguments which are not set explicitly.
+func (c *ImageBuild) getBuildArgs() ([]string, error) {
+	var names []string
+	values := make(map[string]string)
+	addBuildArgs := func(buildArgs []string) error {
+		for _, buildArg := range buildArgs {
+			name, value, err := parseBuildArg(buildArg)
+			if err != nil {
+				return err
+			}
+			if _, exists := values[name]; !exists {
+				names = append(names, name)
+			}
+			values[name] = value
+		}
+		return nil
+	}
+
+	if c.Params.BuildArgsFile != "" {
+		fileBuildArgs, err := readBuildArgsFile(c.Params.BuildArgsFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := addBuildArgs(fileBuildArgs); err != nil {
+			return nil, err
+		}
+	}
+	if err := addBuildArgs(c.Params.BuildArgs); err != nil {
+		return nil, err
+	}
+
+	wellKnownBuildArgs := []struct{ name, value string }{
+		{buildArgCommitSha, c.Params.CommitSha},
+		{buildArgSourceUrl, c.Params.SourceUrl},
+	}
+	for _, buildArg := range wellKnownBuildArgs {
+		if _, exists := values[buildArg.name]; exists || buildArg.value == "" {
+			continue
+		}
+		names = append(names, buildArg.name)
+		values[buildArg.name] = buildArg.value
+	}
+
+	buildArgs := make([]string, 0, len(names))
+	for _, name := range names {
+		buildArgs = append(buildArgs, name+"="+values[name])
+	}
+	return buildArgs, nil
+}
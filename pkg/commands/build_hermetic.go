@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	cliWrappers "github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+const (
+	// prefetchMountPath is where the prefetch dir is mounted in the build, as Konflux prefetch expects.
+	prefetchMountPath = "/cachi2"
+	// prefetchEnvFileName is the env file in the prefetch dir, which configures package managers
+	// to use the prefetched dependencies.
+	prefetchEnvFileName = "cachi2.env"
+)
+
+// applyPrefetchDir mounts the prefetch dir into the build and, if the prefetch env file exists,
+// makes every RUN instruction source it via generated Dockerfile wrapping the original one.
+// Returns cleanup function, which removes the generated Dockerfile.
+func (c *ImageBuild) applyPrefetchDir(buildArgs *cliWrappers.BuildahBuildArgs) (func(), error) {
+	noCleanup := func() {}
+
+	prefetchDir, err := filepath.Abs(c.Params.PrefetchDir)
+	if err != nil {
+		return noCleanup, err
+	}
+	if info, err := os.Stat(prefetchDir); err != nil || !info.IsDir() {
+		return noCleanup, fmt.Errorf("prefetch dir '%s' is not a directory", c.Params.PrefetchDir)
+	}
+	buildArgs.Volumes = append(buildArgs.Volumes, prefetchDir+":"+prefetchMountPath)
+
+	if _, err := os.Stat(filepath.Join(prefetchDir, prefetchEnvFileName)); err != nil {
+		l.Logger.Infof("No %s found in prefetch dir, RUN instructions are not modified", prefetchEnvFileName)
+		return noCleanup, nil
+	}
+
+	dockerfilePath, err := c.getDockerfilePath()
+	if err != nil {
+		return noCleanup, err
+	}
+	dockerfile, err := os.ReadFile(dockerfilePath)
+	if err != nil {
+		return noCleanup, fmt.Errorf("failed to read Dockerfile: %w", err)
+	}
+
+	wrapperDir, err := os.MkdirTemp("", "konflux-build-")
+	if err != nil {
+		return noCleanup, err
+	}
+	cleanup := func() { os.RemoveAll(wrapperDir) }
+	wrapperDockerfilePath := filepath.Join(wrapperDir, "Dockerfile")
+	envFile := prefetchMountPath + "/" + prefetchEnvFileName
+	if err := os.WriteFile(wrapperDockerfilePath, injectRunEnvFile(dockerfile, envFile), 0644); err != nil {
+		cleanup()
+		return noCleanup, fmt.Errorf("failed to write Dockerfile: %w", err)
+	}
+	l.Logger.Infof("Sourcing %s in each RUN instruction of '%s'", envFile, dockerfilePath)
+	buildArgs.DockerfilePath = wrapperDockerfilePath
+
+	return cleanup, nil
+}
+
+// getDockerfilePath returns path to the Dockerfile to build, Containerfile or Dockerfile
+// in the source dir if not set explicitly. Relative paths are relative to the source dir.
+func (c *ImageBuild) getDockerfilePath() (string, error) {
+	if c.Params.DockerfilePath != "" {
+		if filepath.IsAbs(c.Params.DockerfilePath) {
+			return c.Params.DockerfilePath, nil
+		}
+		return filepath.Join(c.Params.SourceDir, c.Params.DockerfilePath), nil
+	}
+	for _, name := range []string{"Containerfile", "Dockerfile"} {
+		path := filepath.Join(c.Params.SourceDir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no Containerfile or Dockerfile found in '%s'", c.Params.SourceDir)
+}
+
+// injectRunEnvFile makes each RUN instruction source the given env file before running its command.
+// Shell form commands are prefixed, exec form commands are wrapped into shell, which execs them.
+// Heredoc RUN instructions are kept unchanged.
+func injectRunEnvFile(dockerfile []byte, envFile string) []byte {
+	lines := strings.Split(string(dockerfile), "\n")
+	sourceEnv := ". " + envFile + " && "
+
+	inInstruction := false
+	// Set when RUN keyword and flags are consumed, but the command starts on a next line
+	pendingRun := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		continues := strings.HasSuffix(trimmed, "\\")
+		// Empty and comment lines don't break multiline instructions
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		rest := ""
+		offset := 0
+		switch {
+		case pendingRun:
+			offset = len(line) - len(strings.TrimLeft(line, " \t"))
+			rest = line[offset:]
+		case !inInstruction:
+			fields := strings.Fields(trimmed)
+			if strings.EqualFold(fields[0], "RUN") {
+				offset = strings.Index(strings.ToUpper(line), "RUN") + len("RUN")
+				rest = line[offset:]
+				pendingRun = true
+			}
+		}
+
+		if pendingRun {
+			// Skip flags, e.g. --mount=type=secret,id=token
+			for {
+				trimmedRest := strings.TrimLeft(rest, " \t")
+				offset += len(rest) - len(trimmedRest)
+				rest = trimmedRest
+				if !strings.HasPrefix(rest, "--") {
+					break
+				}
+				flagEnd := strings.IndexAny(rest, " \t")
+				if flagEnd == -1 {
+					flagEnd = len(rest)
+				}
+				offset += flagEnd
+				rest = rest[flagEnd:]
+			}
+
+			if rest != "" && rest != "\\" {
+				pendingRun = false
+				lines[i] = line[:offset] + wrapRunCommand(rest, sourceEnv)
+			}
+		}
+
+		inInstruction = continues
+		if !continues {
+			pendingRun = false
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// wrapRunCommand makes the RUN command source the env before running.
+func wrapRunCommand(command, sourceEnv string) string {
+	if strings.HasPrefix(command, "<<") {
+		l.Logger.Warnf("Heredoc RUN instruction is not modified: %s", command)
+		return command
+	}
+	if strings.HasPrefix(command, "[") {
+		var execForm []string
+		if err := json.Unmarshal([]byte(command), &execForm); err == nil && len(execForm) > 0 {
+			// sh -c 'script' arg0 args... sets $0 and $@ to the original command
+			wrapped := append([]string{"/bin/sh", "-c", sourceEnv + `exec "$0" "$@"`}, execForm...)
+			var buffer bytes.Buffer
+			encoder := json.NewEncoder(&buffer)
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(wrapped); err == nil {
+				return strings.TrimSuffix(buffer.String(), "\n")
+			}
+		}
+		if strings.HasSuffix(strings.TrimSpace(command), "\\") {
+			l.Logger.Warnf("Multiline exec form RUN instruction is not modified: %s", command)
+			return command
+		}
+		// Not a JSON array, so it's shell form, e.g. RUN [ -f file ] && ...
+	}
+	return sourceEnv + command
+}
+
+// writeHermeticResult records whether the image was built without network access.
+func (c *ImageBuild) writeHermeticResult() error {
+	if c.Results.Hermetic == "" {
+		return nil
+	}
+	return c.ResultsWriter.WriteResultString(strconv.FormatBool(c.Params.Hermetic), c.Results.Hermetic)
+}
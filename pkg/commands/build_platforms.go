@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+
+	cliWrappers "github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+	"github.com/mmorhun/konflux-task-cli/pkg/common"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+var platformRegex = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+(/[a-z0-9]+)?$`)
+
+// qemuArchNames maps architecture names used in platforms to the ones used by qemu binfmt handlers.
+var qemuArchNames = map[string]string{
+	"amd64":   "x86_64",
+	"arm64":   "aarch64",
+	"386":     "i386",
+	"ppc64le": "ppc64le",
+	"s390x":   "s390x",
+	"riscv64": "riscv64",
+	"arm":     "arm",
+}
+
+// getPlatforms returns the requested platforms.
+// Each param value could hold a comma separated list of platforms.
+func (c *ImageBuild) getPlatforms() []string {
+	var platforms []string
+	for _, value := range c.Params.Platforms {
+		for _, platform := range strings.Split(value, ",") {
+			if platform = strings.TrimSpace(platform); platform != "" {
+				platforms = append(platforms, platform)
+			}
+		}
+	}
+	return platforms
+}
+
+// validatePlatforms checks that platforms are in os/arch[/variant] form and aren't repeated.
+func validatePlatforms(platforms []string) error {
+	seen := make(map[string]bool)
+	for _, platform := range platforms {
+		if !platformRegex.MatchString(platform) {
+			return fmt.Errorf("platform '%s' is invalid, expected os/arch[/variant] form, e.g. linux/arm64", platform)
+		}
+		if seen[platform] {
+			return fmt.Errorf("platform '%s' is set more than once", platform)
+		}
+		seen[platform] = true
+	}
+	return nil
+}
+
+// warnIfNoEmulation logs a warning if the platform cannot be run on the host,
+// i.e. it has different architecture and no qemu binfmt handler is registered.
+func warnIfNoEmulation(platform string) {
+	arch := strings.Split(platform, "/")[1]
+	if arch == runtime.GOARCH {
+		return
+	}
+	qemuArch, known := qemuArchNames[arch]
+	if !known {
+		return
+	}
+	if _, err := os.Stat("/proc/sys/fs/binfmt_misc/qemu-" + qemuArch); err != nil {
+		l.Logger.Warnf("No emulation for '%s' platform found, RUN instructions will fail", platform)
+	}
+}
+
+// getImageRepository returns the image reference without tag and digest,
+// e.g. "registry:5000/org/app" for "registry:5000/org/app:v1@sha256:...".
+func getImageRepository(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	// Tag is after the last slash, registry port is before it
+	if lastColon := strings.LastIndex(image, ":"); lastColon > strings.LastIndex(image, "/") {
+		image = image[:lastColon]
+	}
+	return image
+}
+
+// buildPlatforms builds the image for each platform, pushes all the images within an image index
+// and writes the index digest and per platform digests into results.
+func (c *ImageBuild) buildPlatforms(buildArgs *cliWrappers.BuildahBuildArgs, platforms []string) error {
+	buildahCli := c.CliWrappers.BuildahCli
+	manifest := c.Params.Image
+
+	if err := buildahCli.ManifestCreate(manifest); err != nil {
+		return err
+	}
+	for _, platform := range platforms {
+		warnIfNoEmulation(platform)
+		l.Logger.Infof("Building image for '%s' platform", platform)
+
+		platformBuildArgs := *buildArgs
+		platformBuildArgs.Platform = platform
+		// Local name of the platform image, it's pushed by digest within the index
+		platformBuildArgs.Image = getImageRepository(c.Params.Image) + "-" + strings.ReplaceAll(platform, "/", "-")
+		platformImage, _, err := buildahCli.Build(&platformBuildArgs)
+		if err != nil {
+			return fmt.Errorf("failed to build image for '%s' platform: %w", platform, err)
+		}
+		if err := buildahCli.ManifestAdd(manifest, platformImage); err != nil {
+			return err
+		}
+	}
+
+	indexDigest, err := buildahCli.ManifestPush(manifest)
+	if err != nil {
+		return err
+	}
+
+	// Layers are compressed on push, so the digests of the pushed platform images differ from the local ones
+	pushedIndex := "docker://" + getImageRepository(c.Params.Image) + "@" + indexDigest
+	manifestList, err := buildahCli.ManifestInspect(pushedIndex)
+	if err != nil {
+		return err
+	}
+	platformDigests := make(map[string]string)
+	for _, descriptor := range manifestList.Manifests {
+		platform := descriptor.Platform.OS + "/" + descriptor.Platform.Architecture
+		if descriptor.Platform.Variant != "" {
+			platform += "/" + descriptor.Platform.Variant
+		}
+		platformDigests[platform] = descriptor.Digest
+	}
+	platformDigestsJson, err := json.Marshal(platformDigests)
+	if err != nil {
+		return err
+	}
+
+	if err := c.ResultsWriter.WriteResultString(c.Params.Image, c.Results.ImageUrl); err != nil {
+		return err
+	}
+	if err := c.ResultsWriter.WriteResultString(indexDigest, c.Results.Digest); err != nil {
+		return err
+	}
+	if err := common.WriteOptionalResultString(c.ResultsWriter, string(platformDigestsJson), c.Results.PlatformDigests); err != nil {
+		return err
+	}
+
+	if c.Params.Verbose {
+		l.Logger.Infof("[result] Image URL: %s", c.Params.Image)
+		l.Logger.Infof("[result] Image index digest: %s", indexDigest)
+		l.Logger.Infof("[result] Platform digests: %s", string(platformDigestsJson))
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	cliWrappers "github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+	"github.com/mmorhun/konflux-task-cli/pkg/redact"
+)
+
+// buildSecretIdRegex matches valid secret ids, which are also valid Kubernetes secret keys.
+var buildSecretIdRegex = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// getBuildSecrets returns secrets for RUN --mount=type=secret instructions.
+// Each file of the secrets dir is a secret with the file name as id, explicit secrets override them.
+// Values of the secrets are registered for redaction, so they never appear in logs.
+func (c *ImageBuild) getBuildSecrets() ([]cliWrappers.BuildahSecret, error) {
+	var ids []string
+	sources := make(map[string]string)
+	addSecret := func(id, src string) {
+		if _, exists := sources[id]; !exists {
+			ids = append(ids, id)
+		}
+		sources[id] = src
+	}
+
+	if c.Params.SecretsDir != "" {
+		entries, err := os.ReadDir(c.Params.SecretsDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secrets dir: %w", err)
+		}
+		for _, entry := range entries {
+			// Kubernetes secret volume keeps the data in ..data and timestamped directories
+			if strings.HasPrefix(entry.Name(), "..") {
+				continue
+			}
+			path := filepath.Join(c.Params.SecretsDir, entry.Name())
+			// Follow symlinks, Kubernetes secret volume files are links into ..data directory
+			info, err := os.Stat(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to access secret file: %w", err)
+			}
+			if !info.Mode().IsRegular() {
+				l.Logger.Warnf("Skipping '%s' in secrets dir, it's not a file", entry.Name())
+				continue
+			}
+			addSecret(entry.Name(), path)
+		}
+	}
+	for _, secret := range c.Params.Secrets {
+		id, src, err := parseBuildSecret(secret)
+		if err != nil {
+			return nil, err
+		}
+		addSecret(id, src)
+	}
+
+	secrets := make([]cliWrappers.BuildahSecret, 0, len(ids))
+	for _, id := range ids {
+		if !buildSecretIdRegex.MatchString(id) {
+			return nil, fmt.Errorf("secret id '%s' is invalid", id)
+		}
+		src, err := filepath.Abs(sources[id])
+		if err != nil {
+			return nil, err
+		}
+		// Buildah secret option is comma separated
+		if strings.Contains(src, ",") {
+			return nil, fmt.Errorf("secret '%s' path must not contain comma", id)
+		}
+		if err := registerSecretFileValue(src); err != nil {
+			return nil, fmt.Errorf("failed to read secret '%s': %w", id, err)
+		}
+		secrets = append(secrets, cliWrappers.BuildahSecret{Id: id, Src: src})
+	}
+	return secrets, nil
+}
+
+// parseBuildSecret splits id=path secret into its id and path.
+func parseBuildSecret(secret string) (string, string, error) {
+	id, src, found := strings.Cut(secret, "=")
+	if !found || src == "" {
+		return "", "", fmt.Errorf("secret '%s' must be in id=path form", secret)
+	}
+	if !buildSecretIdRegex.MatchString(id) {
+		return "", "", fmt.Errorf("secret id '%s' is invalid", id)
+	}
+	return id, src, nil
+}
+
+// registerSecretFileValue registers content of the secret file for redaction.
+// Lines of multiline secrets are registered too, because commands output may contain some of them only.
+// Short lines, e.g. braces of JSON, are ignored by redaction, short value is reported with a warning.
+func registerSecretFileValue(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	value := string(data)
+	if err := redact.AddSecret(value); err != nil {
+		l.Logger.Warnf("Value of '%s' secret file is not masked in logs: %v", path, err)
+	}
+	for _, line := range strings.Split(value, "\n") {
+		_ = redact.AddSecret(line)
+	}
+	return nil
+}
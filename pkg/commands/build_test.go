@@ -0,0 +1,707 @@
+package commands_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+	"github.com/mmorhun/konflux-task-cli/pkg/commands"
+	"github.com/mmorhun/konflux-task-cli/pkg/common"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+	"github.com/mmorhun/konflux-task-cli/pkg/redact"
+)
+
+const (
+	buildImage            = "quay.io/org/app:tag"
+	resultImageUrlPath    = "/result/dir/image_url"
+	resultImageDigestPath = "/result/dir/image_digest"
+)
+
+func setupTestImageBuild(mockResultsWriter *MockResultsWriter, mockBuildahCli *MockBuildahCli) *commands.ImageBuild {
+	return &commands.ImageBuild{
+		Params: &commands.ImageBuildParams{
+			Image:     buildImage,
+			SourceDir: "source",
+		},
+		Results: &commands.ImageBuildResultFilesPath{
+			ImageUrl: resultImageUrlPath,
+			Digest:   resultImageDigestPath,
+		},
+		ResultsWriter: mockResultsWriter,
+		CliWrappers: commands.ImageBuildCliWrappers{
+			BuildahCli: mockBuildahCli,
+		},
+	}
+}
+
+func TestImageBuild_Success(t *testing.T) {
+	g := NewWithT(t)
+
+	mockBuildahCli := &MockBuildahCli{}
+	mockResultsWriter := &MockResultsWriter{}
+	imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+	imageBuild.Params.Labels = []string{"name=app"}
+
+	mockBuildahCli.BuildFunc = func(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+		g.Expect(args.Image).To(Equal(buildImage))
+		g.Expect(args.SourceDir).To(Equal("source"))
+		g.Expect(args.Labels).To(Equal([]string{"name=app"}))
+		g.Expect(args.BuildArgs).To(BeEmpty())
+		return buildImage, "sha256:local", nil
+	}
+	mockBuildahCli.PushFunc = func(image string) (string, error) {
+		g.Expect(image).To(Equal(buildImage))
+		return "sha256:remote", nil
+	}
+
+	err := imageBuild.Run()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mockResultsWriter.WrittenResults).To(Equal(map[string]string{
+		resultImageUrlPath:    buildImage,
+		resultImageDigestPath: "sha256:remote",
+	}))
+}
+
+func TestImageBuild_BuildArgs(t *testing.T) {
+	g := NewWithT(t)
+
+	buildArgsFile := filepath.Join(t.TempDir(), "build-args")
+	buildArgsFileContent := "# base image\nBASE=ubi9\n\nVERSION=1.0\n  TARGET=prod \nEMPTY=\n"
+	g.Expect(os.WriteFile(buildArgsFile, []byte(buildArgsFileContent), 0644)).To(Succeed())
+
+	mockBuildahCli := &MockBuildahCli{}
+	mockResultsWriter := &MockResultsWriter{}
+	imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+	imageBuild.Params.BuildArgsFile = buildArgsFile
+	imageBuild.Params.BuildArgs = []string{"VERSION=2.0", "DEBUG=a=b"}
+	imageBuild.Params.CommitSha = gitSha
+	imageBuild.Params.SourceUrl = repoUrl
+
+	var buildArgs []string
+	mockBuildahCli.BuildFunc = func(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+		buildArgs = args.BuildArgs
+		return args.Image, "sha256:local", nil
+	}
+
+	err := imageBuild.Run()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(buildArgs).To(Equal([]string{
+		"BASE=ubi9",
+		"VERSION=2.0",
+		"TARGET=prod",
+		"EMPTY=",
+		"DEBUG=a=b",
+		"COMMIT_SHA=" + gitSha,
+		"SOURCE_URL=" + repoUrl,
+	}))
+}
+
+func TestImageBuild_BuildArgs_WellKnownOverridden(t *testing.T) {
+	g := NewWithT(t)
+
+	mockBuildahCli := &MockBuildahCli{}
+	mockResultsWriter := &MockResultsWriter{}
+	imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+	imageBuild.Params.BuildArgs = []string{"COMMIT_SHA=custom"}
+	imageBuild.Params.CommitSha = gitSha
+
+	var buildArgs []string
+	mockBuildahCli.BuildFunc = func(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+		buildArgs = args.BuildArgs
+		return args.Image, "sha256:local", nil
+	}
+
+	err := imageBuild.Run()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(buildArgs).To(Equal([]string{"COMMIT_SHA=custom"}))
+}
+
+func TestImageBuild_BuildArgs_Invalid(t *testing.T) {
+	testCases := []struct {
+		name          string
+		buildArgs     []string
+		fileContent   string
+		expectedError string
+	}{
+		{
+			name:          "param without value",
+			buildArgs:     []string{"VERSION"},
+			expectedError: "build arg 'VERSION' must be in KEY=VALUE form",
+		},
+		{
+			name:          "param with invalid name",
+			buildArgs:     []string{"1VERSION=1"},
+			expectedError: "build arg name '1VERSION' is invalid",
+		},
+		{
+			name:          "file line without value",
+			fileContent:   "BASE=ubi9\nVERSION\n",
+			expectedError: "build args file line 2: build arg 'VERSION' must be in KEY=VALUE form",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockBuildahCli := &MockBuildahCli{}
+			mockResultsWriter := &MockResultsWriter{}
+			imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+			imageBuild.Params.BuildArgs = tc.buildArgs
+			if tc.fileContent != "" {
+				imageBuild.Params.BuildArgsFile = filepath.Join(t.TempDir(), "build-args")
+				g.Expect(os.WriteFile(imageBuild.Params.BuildArgsFile, []byte(tc.fileContent), 0644)).To(Succeed())
+			}
+
+			mockBuildahCli.BuildFunc = func(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+				g.Fail("build must not be called")
+				return "", "", nil
+			}
+
+			err := imageBuild.Run()
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+		})
+	}
+}
+
+func TestImageBuild_BuildArgs_MissingFile(t *testing.T) {
+	g := NewWithT(t)
+
+	mockBuildahCli := &MockBuildahCli{}
+	mockResultsWriter := &MockResultsWriter{}
+	imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+	imageBuild.Params.BuildArgsFile = filepath.Join(t.TempDir(), "missing")
+
+	err := imageBuild.Run()
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to read build args file"))
+}
+
+// fakeRegistry is a stand-in for local containers storage and remote registry,
+// which records built platform images, assembles the manifest list and "pushes" it.
+// Pushed images get different digests, as layers are compressed on push.
+type fakeRegistry struct {
+	manifests map[string][]cliwrappers.BuildahManifestDescriptor
+	// local image name => platform
+	images map[string]string
+	pushed []string
+	// pushed index digest => pushed images
+	indexes   map[string][]cliwrappers.BuildahManifestDescriptor
+	inspected []string
+}
+
+func pushedDigestOf(platform string) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("pushed "+platform)))
+}
+
+func newFakeRegistry(mockBuildahCli *MockBuildahCli) *fakeRegistry {
+	registry := &fakeRegistry{
+		manifests: make(map[string][]cliwrappers.BuildahManifestDescriptor),
+		images:    make(map[string]string),
+		indexes:   make(map[string][]cliwrappers.BuildahManifestDescriptor),
+	}
+	mockBuildahCli.BuildFunc = func(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+		registry.images[args.Image] = args.Platform
+		return args.Image, "sha256:local", nil
+	}
+	mockBuildahCli.ManifestCreateFunc = func(manifest string) error {
+		if _, exists := registry.manifests[manifest]; exists {
+			return errors.New("manifest exists")
+		}
+		registry.manifests[manifest] = []cliwrappers.BuildahManifestDescriptor{}
+		return nil
+	}
+	mockBuildahCli.ManifestAddFunc = func(manifest, image string) error {
+		platform, exists := registry.images[image]
+		if !exists {
+			return errors.New("image not found")
+		}
+		platformParts := strings.Split(platform, "/")
+		descriptor := cliwrappers.BuildahManifestDescriptor{
+			Digest:   "sha256:" + fmt.Sprintf("%x", sha256.Sum256([]byte(platform))),
+			Platform: cliwrappers.BuildahManifestPlatform{OS: platformParts[0], Architecture: platformParts[1]},
+		}
+		if len(platformParts) == 3 {
+			descriptor.Platform.Variant = platformParts[2]
+		}
+		registry.manifests[manifest] = append(registry.manifests[manifest], descriptor)
+		return nil
+	}
+	mockBuildahCli.ManifestPushFunc = func(manifest string) (string, error) {
+		registry.pushed = append(registry.pushed, manifest)
+		indexDigest := "sha256:" + strings.Repeat("d", 64)
+		var pushedImages []cliwrappers.BuildahManifestDescriptor
+		for _, descriptor := range registry.manifests[manifest] {
+			platform := descriptor.Platform.OS + "/" + descriptor.Platform.Architecture
+			if descriptor.Platform.Variant != "" {
+				platform += "/" + descriptor.Platform.Variant
+			}
+			descriptor.Digest = pushedDigestOf(platform)
+			pushedImages = append(pushedImages, descriptor)
+		}
+		registry.indexes[indexDigest] = pushedImages
+		return indexDigest, nil
+	}
+	mockBuildahCli.ManifestInspectFunc = func(manifest string) (*cliwrappers.BuildahManifestList, error) {
+		registry.inspected = append(registry.inspected, manifest)
+		if remoteImage, isRemote := strings.CutPrefix(manifest, "docker://"); isRemote {
+			_, indexDigest, _ := strings.Cut(remoteImage, "@")
+			manifests, exists := registry.indexes[indexDigest]
+			if !exists {
+				return nil, errors.New("image index not found")
+			}
+			return &cliwrappers.BuildahManifestList{Manifests: manifests}, nil
+		}
+		return &cliwrappers.BuildahManifestList{Manifests: registry.manifests[manifest]}, nil
+	}
+	mockBuildahCli.PushFunc = func(image string) (string, error) {
+		return "", errors.New("single image must not be pushed")
+	}
+	return registry
+}
+
+func TestImageBuild_Platforms(t *testing.T) {
+	g := NewWithT(t)
+
+	mockBuildahCli := &MockBuildahCli{}
+	mockResultsWriter := &MockResultsWriter{}
+	imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+	imageBuild.Params.Platforms = []string{"linux/amd64,linux/arm64", "linux/arm/v7"}
+	imageBuild.Params.Labels = []string{"name=app"}
+	imageBuild.Results.PlatformDigests = "/result/dir/platform_digests"
+
+	registry := newFakeRegistry(mockBuildahCli)
+
+	err := imageBuild.Run()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(registry.images).To(Equal(map[string]string{
+		"quay.io/org/app-linux-amd64":  "linux/amd64",
+		"quay.io/org/app-linux-arm64":  "linux/arm64",
+		"quay.io/org/app-linux-arm-v7": "linux/arm/v7",
+	}))
+	g.Expect(registry.manifests[buildImage]).To(HaveLen(3))
+	g.Expect(registry.pushed).To(Equal([]string{buildImage}))
+
+	g.Expect(registry.inspected).To(Equal([]string{"docker://quay.io/org/app@sha256:" + strings.Repeat("d", 64)}))
+
+	g.Expect(mockResultsWriter.WrittenResults).To(HaveKeyWithValue(resultImageUrlPath, buildImage))
+	g.Expect(mockResultsWriter.WrittenResults).To(HaveKeyWithValue(resultImageDigestPath, "sha256:"+strings.Repeat("d", 64)))
+	g.Expect(mockResultsWriter.WrittenResults).To(HaveKey("/result/dir/platform_digests"))
+	var platformDigests map[string]string
+	g.Expect(json.Unmarshal([]byte(mockResultsWriter.WrittenResults["/result/dir/platform_digests"]), &platformDigests)).To(Succeed())
+	g.Expect(platformDigests).To(Equal(map[string]string{
+		"linux/amd64":  pushedDigestOf("linux/amd64"),
+		"linux/arm64":  pushedDigestOf("linux/arm64"),
+		"linux/arm/v7": pushedDigestOf("linux/arm/v7"),
+	}))
+}
+
+func TestImageBuild_Platforms_LocalImageName(t *testing.T) {
+	for image, expectedLocalImage := range map[string]string{
+		"localhost:5000/org/app":                               "localhost:5000/org/app-linux-arm64",
+		"localhost:5000/org/app:v1":                            "localhost:5000/org/app-linux-arm64",
+		"quay.io/org/app:v1@sha256:" + strings.Repeat("a", 64): "quay.io/org/app-linux-arm64",
+	} {
+		t.Run(image, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockBuildahCli := &MockBuildahCli{}
+			mockResultsWriter := &MockResultsWriter{}
+			imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+			imageBuild.Params.Image = image
+			imageBuild.Params.Platforms = []string{"linux/arm64"}
+
+			registry := newFakeRegistry(mockBuildahCli)
+
+			err := imageBuild.Run()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(registry.images).To(Equal(map[string]string{expectedLocalImage: "linux/arm64"}))
+			// Platform digests result is not requested
+			g.Expect(mockResultsWriter.WrittenResults).To(HaveLen(2))
+		})
+	}
+}
+
+func TestImageBuild_Platforms_BuildError(t *testing.T) {
+	g := NewWithT(t)
+
+	mockBuildahCli := &MockBuildahCli{}
+	mockResultsWriter := &MockResultsWriter{}
+	imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+	imageBuild.Params.Platforms = []string{"linux/amd64,linux/s390x"}
+
+	registry := newFakeRegistry(mockBuildahCli)
+	registryBuildFunc := mockBuildahCli.BuildFunc
+	mockBuildahCli.BuildFunc = func(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+		if args.Platform == "linux/s390x" {
+			return "", "", errors.New("exec format error")
+		}
+		return registryBuildFunc(args)
+	}
+
+	err := imageBuild.Run()
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to build image for 'linux/s390x' platform"))
+	g.Expect(registry.pushed).To(BeEmpty())
+	g.Expect(mockResultsWriter.WrittenResults).To(BeEmpty())
+}
+
+func TestImageBuild_Platforms_Invalid(t *testing.T) {
+	testCases := []struct {
+		name          string
+		platforms     []string
+		expectedError string
+	}{
+		{
+			name:          "missing architecture",
+			platforms:     []string{"linux"},
+			expectedError: "platform 'linux' is invalid",
+		},
+		{
+			name:          "option like",
+			platforms:     []string{"--all"},
+			expectedError: "platform '--all' is invalid",
+		},
+		{
+			name:          "repeated",
+			platforms:     []string{"linux/amd64,linux/arm64", "linux/amd64"},
+			expectedError: "platform 'linux/amd64' is set more than once",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockBuildahCli := &MockBuildahCli{}
+			mockResultsWriter := &MockResultsWriter{}
+			imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+			imageBuild.Params.Platforms = tc.platforms
+
+			mockBuildahCli.BuildFunc = func(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+				g.Fail("build must not be called")
+				return "", "", nil
+			}
+
+			err := imageBuild.Run()
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+		})
+	}
+}
+
+// createKubernetesSecretVolume creates a directory with the layout of Kubernetes secret volume:
+// the data is in a timestamped directory, linked via ..data, and each key is a link into ..data.
+func createKubernetesSecretVolume(g *WithT, dir string, data map[string]string) {
+	dataDir := filepath.Join(dir, "..2026_01_01_00_00_00.123456789")
+	g.Expect(os.Mkdir(dataDir, 0755)).To(Succeed())
+	for key, value := range data {
+		g.Expect(os.WriteFile(filepath.Join(dataDir, key), []byte(value), 0644)).To(Succeed())
+		g.Expect(os.Symlink(filepath.Join("..data", key), filepath.Join(dir, key))).To(Succeed())
+	}
+	g.Expect(os.Symlink(filepath.Base(dataDir), filepath.Join(dir, "..data"))).To(Succeed())
+}
+
+func TestImageBuild_Secrets(t *testing.T) {
+	g := NewWithT(t)
+	defer redact.ResetSecrets()
+
+	secretsDir := t.TempDir()
+	createKubernetesSecretVolume(g, secretsDir, map[string]string{
+		"token":             "s3cr3t-token-value",
+		".dockerconfigjson": "{\n  \"auths\": {\"quay.io\": {\"auth\": \"dXNlcjpwYXNz\"}}\n}\n",
+	})
+	g.Expect(os.Mkdir(filepath.Join(secretsDir, "subdir"), 0755)).To(Succeed())
+	explicitSecret := filepath.Join(t.TempDir(), "override")
+	g.Expect(os.WriteFile(explicitSecret, []byte("overridden-token-value"), 0600)).To(Succeed())
+
+	mockBuildahCli := &MockBuildahCli{}
+	mockResultsWriter := &MockResultsWriter{}
+	imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+	imageBuild.Params.SecretsDir = secretsDir
+	imageBuild.Params.Secrets = []string{"token=" + explicitSecret, "netrc=" + explicitSecret}
+
+	var secrets []cliwrappers.BuildahSecret
+	mockBuildahCli.BuildFunc = func(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+		secrets = args.Secrets
+		return args.Image, "sha256:local", nil
+	}
+
+	err := imageBuild.Run()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(secrets).To(Equal([]cliwrappers.BuildahSecret{
+		{Id: ".dockerconfigjson", Src: filepath.Join(secretsDir, ".dockerconfigjson")},
+		{Id: "token", Src: explicitSecret},
+		{Id: "netrc", Src: explicitSecret},
+	}))
+
+	// Secret values are masked in logs, including separate lines of multiline secrets
+	g.Expect(redact.String("token: overridden-token-value")).To(Equal("token: " + redact.Mask))
+	g.Expect(redact.String(`"auths": {"quay.io": {"auth": "dXNlcjpwYXNz"}}`)).To(Equal(redact.Mask))
+	// Overridden secret from the secrets dir isn't used and isn't registered
+	g.Expect(redact.String("s3cr3t-token-value")).To(Equal("s3cr3t-token-value"))
+}
+
+func TestImageBuild_Secrets_NotLoggedByExecutor(t *testing.T) {
+	g := NewWithT(t)
+	defer redact.ResetSecrets()
+
+	secretsDir := t.TempDir()
+	createKubernetesSecretVolume(g, secretsDir, map[string]string{"token": "s3cr3t-token-value\n"})
+
+	mockBuildahCli := &MockBuildahCli{}
+	mockResultsWriter := &MockResultsWriter{}
+	imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+	imageBuild.Params.SecretsDir = secretsDir
+	imageBuild.Params.Verbose = true
+
+	var output bytes.Buffer
+	l.Logger.SetOutput(&output)
+	defer l.Logger.SetOutput(os.Stderr)
+
+	mockBuildahCli.BuildFunc = func(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+		// Simulate a build step which prints the secret, the output is logged as buildah does in verbose mode
+		executor := cliwrappers.NewCliExecutor(true)
+		stdout, _, _, err := executor.Execute("echo", "token:", "s3cr3t-token-value")
+		l.Logger.Info("[stdout]:\n" + stdout)
+		return args.Image, "sha256:local", err
+	}
+
+	err := imageBuild.Run()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(output.String()).To(ContainSubstring(redact.Mask))
+	g.Expect(output.String()).NotTo(ContainSubstring("s3cr3t-token-value"))
+}
+
+func TestImageBuild_Secrets_Invalid(t *testing.T) {
+	testCases := []struct {
+		name          string
+		secrets       []string
+		secretsDir    string
+		expectedError string
+	}{
+		{
+			name:          "secret without path",
+			secrets:       []string{"token"},
+			expectedError: "secret 'token' must be in id=path form",
+		},
+		{
+			name:          "secret with invalid id",
+			secrets:       []string{"to,ken=/tmp/token"},
+			expectedError: "secret id 'to,ken' is invalid",
+		},
+		{
+			name:          "missing secret file",
+			secrets:       []string{"token=/nonexistent/token"},
+			expectedError: "failed to read secret 'token'",
+		},
+		{
+			name:          "missing secrets dir",
+			secretsDir:    "/nonexistent/secrets",
+			expectedError: "failed to read secrets dir",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockBuildahCli := &MockBuildahCli{}
+			mockResultsWriter := &MockResultsWriter{}
+			imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+			imageBuild.Params.Secrets = tc.secrets
+			imageBuild.Params.SecretsDir = tc.secretsDir
+
+			mockBuildahCli.BuildFunc = func(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+				g.Fail("build must not be called")
+				return "", "", nil
+			}
+
+			err := imageBuild.Run()
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+		})
+	}
+}
+
+const resultHermeticPath = "/result/dir/hermetic"
+
+func TestImageBuild_Hermetic(t *testing.T) {
+	g := NewWithT(t)
+
+	sourceDir := t.TempDir()
+	dockerfile := `# syntax=docker/dockerfile:1
+FROM registry.access.redhat.com/ubi9/ubi AS builder
+RUN pip install -r requirements.txt
+run --mount=type=secret,id=token \
+    --network=none \
+    make build && \
+    make test
+RUN ["go", "build", "./..."]
+RUN [ -f setup.py ] && python setup.py install
+RUN \
+  # comment inside instruction
+  npm ci
+RUN <<EOF
+echo heredoc
+EOF
+ENV RUN=1
+COPY . .
+FROM builder
+CMD ["/app"]
+`
+	g.Expect(os.WriteFile(filepath.Join(sourceDir, "Containerfile"), []byte(dockerfile), 0644)).To(Succeed())
+	prefetchDir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(prefetchDir, "cachi2.env"), []byte("export GOFLAGS=-mod=vendor\n"), 0644)).To(Succeed())
+
+	mockBuildahCli := &MockBuildahCli{}
+	mockResultsWriter := &MockResultsWriter{}
+	imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+	imageBuild.Params.SourceDir = sourceDir
+	imageBuild.Params.Hermetic = true
+	imageBuild.Params.PrefetchDir = prefetchDir
+	imageBuild.Results.Hermetic = resultHermeticPath
+
+	var generatedDockerfilePath, generatedDockerfile string
+	mockBuildahCli.BuildFunc = func(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+		g.Expect(args.Network).To(Equal("none"))
+		g.Expect(args.Volumes).To(Equal([]string{prefetchDir + ":/cachi2"}))
+		generatedDockerfilePath = args.DockerfilePath
+		content, err := os.ReadFile(args.DockerfilePath)
+		g.Expect(err).NotTo(HaveOccurred())
+		generatedDockerfile = string(content)
+		return args.Image, "sha256:local", nil
+	}
+
+	err := imageBuild.Run()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(generatedDockerfile).To(Equal(`# syntax=docker/dockerfile:1
+FROM registry.access.redhat.com/ubi9/ubi AS builder
+RUN . /cachi2/cachi2.env && pip install -r requirements.txt
+run --mount=type=secret,id=token \
+    --network=none \
+    . /cachi2/cachi2.env && make build && \
+    make test
+RUN ["/bin/sh","-c",". /cachi2/cachi2.env && exec \"$0\" \"$@\"","go","build","./..."]
+RUN . /cachi2/cachi2.env && [ -f setup.py ] && python setup.py install
+RUN \
+  # comment inside instruction
+  . /cachi2/cachi2.env && npm ci
+RUN <<EOF
+echo heredoc
+EOF
+ENV RUN=1
+COPY . .
+FROM builder
+CMD ["/app"]
+`))
+	// The generated Dockerfile is removed after the build
+	_, err = os.Stat(generatedDockerfilePath)
+	g.Expect(os.IsNotExist(err)).To(BeTrue())
+	g.Expect(mockResultsWriter.WrittenResults).To(HaveKeyWithValue(resultHermeticPath, "true"))
+}
+
+func TestImageBuild_PrefetchDirWithoutEnvFile(t *testing.T) {
+	g := NewWithT(t)
+
+	prefetchDir := t.TempDir()
+
+	mockBuildahCli := &MockBuildahCli{}
+	mockResultsWriter := &MockResultsWriter{}
+	imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+	imageBuild.Params.DockerfilePath = "Containerfile"
+	imageBuild.Params.PrefetchDir = prefetchDir
+	imageBuild.Results.Hermetic = resultHermeticPath
+
+	mockBuildahCli.BuildFunc = func(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+		g.Expect(args.Network).To(BeEmpty())
+		g.Expect(args.Volumes).To(Equal([]string{prefetchDir + ":/cachi2"}))
+		g.Expect(args.DockerfilePath).To(Equal("Containerfile"))
+		return args.Image, "sha256:local", nil
+	}
+
+	err := imageBuild.Run()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mockResultsWriter.WrittenResults).To(HaveKeyWithValue(resultHermeticPath, "false"))
+}
+
+func TestImageBuild_Hermetic_Platforms(t *testing.T) {
+	g := NewWithT(t)
+
+	mockBuildahCli := &MockBuildahCli{}
+	mockResultsWriter := &MockResultsWriter{}
+	imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+	imageBuild.Params.Hermetic = true
+	imageBuild.Params.Platforms = []string{"linux/amd64"}
+	imageBuild.Results.Hermetic = resultHermeticPath
+
+	registry := newFakeRegistry(mockBuildahCli)
+	registryBuildFunc := mockBuildahCli.BuildFunc
+	mockBuildahCli.BuildFunc = func(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+		g.Expect(args.Network).To(Equal("none"))
+		return registryBuildFunc(args)
+	}
+
+	err := imageBuild.Run()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(registry.pushed).To(Equal([]string{buildImage}))
+	g.Expect(mockResultsWriter.WrittenResults).To(HaveKeyWithValue(resultHermeticPath, "true"))
+}
+
+func TestImageBuild_PrefetchDir_Invalid(t *testing.T) {
+	g := NewWithT(t)
+
+	mockBuildahCli := &MockBuildahCli{}
+	mockResultsWriter := &MockResultsWriter{}
+	imageBuild := setupTestImageBuild(mockResultsWriter, mockBuildahCli)
+	imageBuild.Params.Hermetic = true
+	imageBuild.Params.PrefetchDir = filepath.Join(t.TempDir(), "missing")
+
+	mockBuildahCli.BuildFunc = func(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+		g.Fail("build must not be called")
+		return "", "", nil
+	}
+
+	err := imageBuild.Run()
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("is not a directory"))
+}
+
+func TestImageBuild_Secrets_ShortValueDoesNotCorruptResults(t *testing.T) {
+	g := NewWithT(t)
+	defer redact.ResetSecrets()
+
+	secretsDir := t.TempDir()
+	createKubernetesSecretVolume(g, secretsDir, map[string]string{"flag": "1", "enabled": "true", "key": "abc"})
+	resultsDir := t.TempDir()
+
+	mockBuildahCli := &MockBuildahCli{}
+	imageBuild := setupTestImageBuild(nil, mockBuildahCli)
+	imageBuild.ResultsWriter = common.NewResultsWriter(false)
+	imageBuild.Results.ImageUrl = filepath.Join(resultsDir, "image_url")
+	imageBuild.Results.Digest = filepath.Join(resultsDir, "image_digest")
+	imageBuild.Params.SecretsDir = secretsDir
+
+	digest := "sha256:abc1abc1abc1abc1abc1abc1abc1abc1abc1abc1abc1abc1abc1abc1abc1abc1"
+	mockBuildahCli.PushFunc = func(image string) (string, error) {
+		return digest, nil
+	}
+
+	err := imageBuild.Run()
+	g.Expect(err).NotTo(HaveOccurred())
+	writtenDigest, err := os.ReadFile(imageBuild.Results.Digest)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(writtenDigest)).To(Equal(digest))
+}
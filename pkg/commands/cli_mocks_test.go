@@ -1,19 +1,53 @@
 package commands_test
 
 import (
+	"strings"
+
 	"github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
 )
 
 var _ cliwrappers.GitCliInterface = &MockGitCli{}
+var _ cliwrappers.GpgCliInterface = &MockGpgCli{}
+var _ cliwrappers.BuildahCliInterface = &MockBuildahCli{}
 
 type MockGitCli struct {
-	CloneFunc              func(url, branch string, depth int) (string, error)
-	GetRepoHeadFullShaFunc func(gitRepoDir string) (string, error)
+	CloneFunc                  func(args *cliwrappers.GitCloneArgs) (string, error)
+	GetRepoHeadFullShaFunc     func(gitRepoDir string) (string, error)
+	InitFunc                   func(repoDir string) error
+	AddRemoteFunc              func(repoDir, name, url string) error
+	FetchFunc                  func(args *cliwrappers.GitFetchArgs) error
+	CheckoutFunc               func(repoDir, ref string) error
+	GetRemoteUrlFunc           func(repoDir, remote string) (string, error)
+	GetRemoteDefaultBranchFunc func(url string) (string, error)
+	ResetHardFunc              func(repoDir, ref string) error
+	CleanFunc                  func(repoDir string) error
+	RepackFunc                 func(repoDir string) error
+	IsBareRepositoryFunc       func(repoDir string) (bool, error)
+	IsShallowRepositoryFunc    func(repoDir string) (bool, error)
+	MergeBaseFunc              func(repoDir, ref1, ref2 string) (string, error)
+	DiffNamesFunc              func(repoDir, fromRef, toRef string) ([]string, error)
+	SparseCheckoutSetFunc      func(repoDir string, paths []string) error
+	MergeFunc                  func(args *cliwrappers.GitMergeArgs) error
+	SubmoduleUpdateFunc        func(args *cliwrappers.GitSubmoduleUpdateArgs) error
+	ListSubmodulesFunc         func(repoDir string, recursive bool) ([]cliwrappers.GitSubmodule, error)
+	ListDeclaredSubmodulesFunc func(repoDir string) ([]cliwrappers.GitSubmodule, error)
+	GetCommitInfoFunc          func(repoDir, ref string) (*cliwrappers.GitCommitInfo, error)
+	DescribeFunc               func(repoDir string) (string, error)
+	CreateBundleFunc           func(repoDir, bundlePath string) error
+	GetCommitSignatureFunc     func(repoDir, ref string) (*cliwrappers.GitCommitSignature, error)
+	IsLfsAvailableFunc         func() bool
+	LfsInstallFunc             func(repoDir string) error
+	LfsPullFunc                func(args *cliwrappers.GitLfsPullArgs) error
+
+	// Env holds environment variables set via SetEnv
+	Env map[string]string
+	// Config holds git configuration options added via AddConfig
+	Config map[string][]string
 }
 
-func (m *MockGitCli) Clone(url, branch string, depth int) (string, error) {
+func (m *MockGitCli) Clone(args *cliwrappers.GitCloneArgs) (string, error) {
 	if m.CloneFunc != nil {
-		return m.CloneFunc(url, branch, depth)
+		return m.CloneFunc(args)
 	}
 	return "", nil
 }
@@ -24,3 +58,254 @@ func (m *MockGitCli) GetRepoHeadFullSha(gitRepoDir string) (string, error) {
 	}
 	return "", nil
 }
+
+func (m *MockGitCli) Init(repoDir string) error {
+	if m.InitFunc != nil {
+		return m.InitFunc(repoDir)
+	}
+	return nil
+}
+
+func (m *MockGitCli) AddRemote(repoDir, name, url string) error {
+	if m.AddRemoteFunc != nil {
+		return m.AddRemoteFunc(repoDir, name, url)
+	}
+	return nil
+}
+
+func (m *MockGitCli) Fetch(args *cliwrappers.GitFetchArgs) error {
+	if m.FetchFunc != nil {
+		return m.FetchFunc(args)
+	}
+	return nil
+}
+
+func (m *MockGitCli) Checkout(repoDir, ref string) error {
+	if m.CheckoutFunc != nil {
+		return m.CheckoutFunc(repoDir, ref)
+	}
+	return nil
+}
+
+func (m *MockGitCli) GetRemoteUrl(repoDir, remote string) (string, error) {
+	if m.GetRemoteUrlFunc != nil {
+		return m.GetRemoteUrlFunc(repoDir, remote)
+	}
+	return "", nil
+}
+
+func (m *MockGitCli) GetRemoteDefaultBranch(url string) (string, error) {
+	if m.GetRemoteDefaultBranchFunc != nil {
+		return m.GetRemoteDefaultBranchFunc(url)
+	}
+	return "main", nil
+}
+
+func (m *MockGitCli) ResetHard(repoDir, ref string) error {
+	if m.ResetHardFunc != nil {
+		return m.ResetHardFunc(repoDir, ref)
+	}
+	return nil
+}
+
+func (m *MockGitCli) Clean(repoDir string) error {
+	if m.CleanFunc != nil {
+		return m.CleanFunc(repoDir)
+	}
+	return nil
+}
+
+func (m *MockGitCli) Repack(repoDir string) error {
+	if m.RepackFunc != nil {
+		return m.RepackFunc(repoDir)
+	}
+	return nil
+}
+
+func (m *MockGitCli) IsBareRepository(repoDir string) (bool, error) {
+	if m.IsBareRepositoryFunc != nil {
+		return m.IsBareRepositoryFunc(repoDir)
+	}
+	return true, nil
+}
+
+func (m *MockGitCli) IsShallowRepository(repoDir string) (bool, error) {
+	if m.IsShallowRepositoryFunc != nil {
+		return m.IsShallowRepositoryFunc(repoDir)
+	}
+	return false, nil
+}
+
+func (m *MockGitCli) MergeBase(repoDir, ref1, ref2 string) (string, error) {
+	if m.MergeBaseFunc != nil {
+		return m.MergeBaseFunc(repoDir, ref1, ref2)
+	}
+	return "", nil
+}
+
+func (m *MockGitCli) DiffNames(repoDir, fromRef, toRef string) ([]string, error) {
+	if m.DiffNamesFunc != nil {
+		return m.DiffNamesFunc(repoDir, fromRef, toRef)
+	}
+	return []string{}, nil
+}
+
+func (m *MockGitCli) SparseCheckoutSet(repoDir string, paths []string) error {
+	if m.SparseCheckoutSetFunc != nil {
+		return m.SparseCheckoutSetFunc(repoDir, paths)
+	}
+	return nil
+}
+
+func (m *MockGitCli) Merge(args *cliwrappers.GitMergeArgs) error {
+	if m.MergeFunc != nil {
+		return m.MergeFunc(args)
+	}
+	return nil
+}
+
+func (m *MockGitCli) SubmoduleUpdate(args *cliwrappers.GitSubmoduleUpdateArgs) error {
+	if m.SubmoduleUpdateFunc != nil {
+		return m.SubmoduleUpdateFunc(args)
+	}
+	return nil
+}
+
+func (m *MockGitCli) ListSubmodules(repoDir string, recursive bool) ([]cliwrappers.GitSubmodule, error) {
+	if m.ListSubmodulesFunc != nil {
+		return m.ListSubmodulesFunc(repoDir, recursive)
+	}
+	return nil, nil
+}
+
+func (m *MockGitCli) ListDeclaredSubmodules(repoDir string) ([]cliwrappers.GitSubmodule, error) {
+	if m.ListDeclaredSubmodulesFunc != nil {
+		return m.ListDeclaredSubmodulesFunc(repoDir)
+	}
+	return nil, nil
+}
+
+func (m *MockGitCli) GetCommitInfo(repoDir, ref string) (*cliwrappers.GitCommitInfo, error) {
+	if m.GetCommitInfoFunc != nil {
+		return m.GetCommitInfoFunc(repoDir, ref)
+	}
+	return &cliwrappers.GitCommitInfo{}, nil
+}
+
+func (m *MockGitCli) Describe(repoDir string) (string, error) {
+	if m.DescribeFunc != nil {
+		return m.DescribeFunc(repoDir)
+	}
+	return "", nil
+}
+
+func (m *MockGitCli) CreateBundle(repoDir, bundlePath string) error {
+	if m.CreateBundleFunc != nil {
+		return m.CreateBundleFunc(repoDir, bundlePath)
+	}
+	return nil
+}
+
+func (m *MockGitCli) GetCommitSignature(repoDir, ref string) (*cliwrappers.GitCommitSignature, error) {
+	if m.GetCommitSignatureFunc != nil {
+		return m.GetCommitSignatureFunc(repoDir, ref)
+	}
+	return &cliwrappers.GitCommitSignature{Status: cliwrappers.GitSignatureStatusGood}, nil
+}
+
+func (m *MockGitCli) IsLfsAvailable() bool {
+	if m.IsLfsAvailableFunc != nil {
+		return m.IsLfsAvailableFunc()
+	}
+	return true
+}
+
+func (m *MockGitCli) LfsInstall(repoDir string) error {
+	if m.LfsInstallFunc != nil {
+		return m.LfsInstallFunc(repoDir)
+	}
+	return nil
+}
+
+func (m *MockGitCli) LfsPull(args *cliwrappers.GitLfsPullArgs) error {
+	if m.LfsPullFunc != nil {
+		return m.LfsPullFunc(args)
+	}
+	return nil
+}
+
+func (m *MockGitCli) SetEnv(name, value string) {
+	if m.Env == nil {
+		m.Env = make(map[string]string)
+	}
+	m.Env[name] = value
+}
+
+func (m *MockGitCli) AddConfig(key, value string) {
+	if m.Config == nil {
+		m.Config = make(map[string][]string)
+	}
+	m.Config[key] = append(m.Config[key], value)
+}
+
+type MockGpgCli struct {
+	ImportKeysFunc func(homeDir, keyringPath string) error
+}
+
+func (m *MockGpgCli) ImportKeys(homeDir, keyringPath string) error {
+	if m.ImportKeysFunc != nil {
+		return m.ImportKeysFunc(homeDir, keyringPath)
+	}
+	return nil
+}
+
+type MockBuildahCli struct {
+	BuildFunc           func(args *cliwrappers.BuildahBuildArgs) (string, string, error)
+	PushFunc            func(image string) (string, error)
+	ManifestCreateFunc  func(manifest string) error
+	ManifestAddFunc     func(manifest, image string) error
+	ManifestPushFunc    func(manifest string) (string, error)
+	ManifestInspectFunc func(manifest string) (*cliwrappers.BuildahManifestList, error)
+}
+
+func (m *MockBuildahCli) Build(args *cliwrappers.BuildahBuildArgs) (string, string, error) {
+	if m.BuildFunc != nil {
+		return m.BuildFunc(args)
+	}
+	return args.Image, "sha256:" + strings.Repeat("a", 64), nil
+}
+
+func (m *MockBuildahCli) Push(image string) (string, error) {
+	if m.PushFunc != nil {
+		return m.PushFunc(image)
+	}
+	return "sha256:" + strings.Repeat("b", 64), nil
+}
+
+func (m *MockBuildahCli) ManifestCreate(manifest string) error {
+	if m.ManifestCreateFunc != nil {
+		return m.ManifestCreateFunc(manifest)
+	}
+	return nil
+}
+
+func (m *MockBuildahCli) ManifestAdd(manifest, image string) error {
+	if m.ManifestAddFunc != nil {
+		return m.ManifestAddFunc(manifest, image)
+	}
+	return nil
+}
+
+func (m *MockBuildahCli) ManifestPush(manifest string) (string, error) {
+	if m.ManifestPushFunc != nil {
+		return m.ManifestPushFunc(manifest)
+	}
+	return "sha256:" + strings.Repeat("c", 64), nil
+}
+
+func (m *MockBuildahCli) ManifestInspect(manifest string) (*cliwrappers.BuildahManifestList, error) {
+	if m.ManifestInspectFunc != nil {
+		return m.ManifestInspectFunc(manifest)
+	}
+	return &cliwrappers.BuildahManifestList{}, nil
+}
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"fmt"
+	"reflect"
+
+	cliWrappers "github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+	"github.com/mmorhun/konflux-task-cli/pkg/common"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+const (
+	gitBackendAuto  = "auto"
+	gitBackendCli   = "cli"
+	gitBackendGoGit = "go-git"
+)
+
+var gitBackendParam = common.Parameter{
+	Name:         "git-backend",
+	EnvVarName:   "GIT_BACKEND",
+	TypeKind:     reflect.String,
+	DefaultValue: gitBackendAuto,
+	Usage:        "Git implementation to use: cli, go-git or auto, which selects go-git if git CLI is not installed",
+}
+
+// newGitBackend creates git implementation according to the backend parameter value.
+func newGitBackend(backend string, executor cliWrappers.CliExecutorInterface, verbose bool) (cliWrappers.GitCliInterface, error) {
+	switch backend {
+	case gitBackendCli:
+		return cliWrappers.NewGitCli(executor, verbose)
+	case gitBackendGoGit:
+		return cliWrappers.NewGoGit(verbose), nil
+	case gitBackendAuto, "":
+		isGitAvailable, _ := cliWrappers.CheckCliToolAvailable("git")
+		if isGitAvailable {
+			return cliWrappers.NewGitCli(executor, verbose)
+		}
+		l.Logger.Info("git CLI is not available, using go-git backend")
+		return cliWrappers.NewGoGit(verbose), nil
+	default:
+		return nil, fmt.Errorf("git backend '%s' is invalid, supported values: %s, %s, %s",
+			backend, gitBackendAuto, gitBackendCli, gitBackendGoGit)
+	}
+}
@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	cliWrappers "github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+	"github.com/mmorhun/konflux-task-cli/pkg/common"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+var GitMirrorUpdateParamsConfig = map[string]common.Parameter{
+	"url": {
+		Name:       "url",
+		EnvVarName: "GIT_REPO_URL",
+		TypeKind:   reflect.String,
+		Usage:      "Git URL of the repository to mirror",
+		Required:   true,
+	},
+	"mirror-dir": {
+		Name:       "mirror-dir",
+		EnvVarName: "GIT_MIRROR_DIR",
+		TypeKind:   reflect.String,
+		Usage:      "Directory of the bare mirror to create or refresh",
+		Required:   true,
+	},
+	"http-proxy":  httpProxyParam,
+	"https-proxy": httpsProxyParam,
+	"no-proxy":    noProxyParam,
+	"ca-bundle":   caBundleParam,
+	"git-backend": gitBackendParam,
+	"verbose": {
+		Name:         "verbose",
+		ShortName:    "v",
+		EnvVarName:   "VERBOSE",
+		TypeKind:     reflect.Bool,
+		DefaultValue: "false",
+		Usage:        "Activates verbose mode",
+	},
+}
+
+type GitMirrorUpdateParams struct {
+	NetworkParams
+
+	RepoUrl    string `paramName:"url"`
+	MirrorDir  string `paramName:"mirror-dir"`
+	GitBackend string `paramName:"git-backend"`
+	Verbose    bool   `paramName:"verbose"`
+}
+
+type GitMirrorUpdateCliWrappers struct {
+	GitCli cliWrappers.GitCliInterface
+}
+
+type GitMirrorUpdate struct {
+	Params      *GitMirrorUpdateParams
+	CliWrappers GitMirrorUpdateCliWrappers
+}
+
+func NewGitMirrorUpdate(cmd *cobra.Command) (*GitMirrorUpdate, error) {
+	gitMirrorUpdate := &GitMirrorUpdate{}
+
+	params := &GitMirrorUpdateParams{}
+	if err := common.ParseParameters(cmd, GitMirrorUpdateParamsConfig, params); err != nil {
+		return nil, err
+	}
+	gitMirrorUpdate.Params = params
+
+	if err := gitMirrorUpdate.initCliWrappers(); err != nil {
+		return nil, err
+	}
+
+	return gitMirrorUpdate, nil
+}
+
+func (c *GitMirrorUpdate) initCliWrappers() error {
+	gitCli, err := newNetworkGitBackend(c.Params.GitBackend, c.Params.networkConfig(), c.Params.Verbose)
+	if err != nil {
+		return err
+	}
+	c.CliWrappers.GitCli = gitCli
+	return nil
+}
+
+func (c *GitMirrorUpdate) Run() error {
+	if c.Params.Verbose {
+		l.Logger.Infof("[param] repository: %s", c.Params.RepoUrl)
+		if c.Params.GitBackend != "" {
+			l.Logger.Infof("[param] git backend: %s", c.Params.GitBackend)
+		}
+		l.Logger.Infof("[param] mirror dir: %s", c.Params.MirrorDir)
+		logNetworkConfig(c.Params.networkConfig())
+	}
+
+	if err := c.validateParams(); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(filepath.Join(c.Params.MirrorDir, "HEAD")); err == nil {
+		err := c.updateMirror()
+		if err == nil {
+			l.Logger.Infof("Updated mirror in '%s'", c.Params.MirrorDir)
+			return nil
+		}
+		l.Logger.Warnf("Failed to update mirror in '%s', creating it from scratch: %s", c.Params.MirrorDir, err.Error())
+	}
+
+	if err := common.RemoveDirContent(c.Params.MirrorDir); err != nil {
+		return fmt.Errorf("failed to delete mirror directory content: %w", err)
+	}
+	cloneArgs := &cliWrappers.GitCloneArgs{
+		Url:       c.Params.RepoUrl,
+		Directory: c.Params.MirrorDir,
+		Mirror:    true,
+	}
+	if _, err := c.CliWrappers.GitCli.Clone(cloneArgs); err != nil {
+		return fmt.Errorf("failed to create mirror: %w", err)
+	}
+	l.Logger.Infof("Created mirror in '%s'", c.Params.MirrorDir)
+	return nil
+}
+
+// updateMirror fetches all refs into the existing mirror of the same repository.
+func (c *GitMirrorUpdate) updateMirror() error {
+	isBare, err := c.CliWrappers.GitCli.IsBareRepository(c.Params.MirrorDir)
+	if err != nil {
+		return err
+	}
+	if !isBare {
+		return errors.New("mirror is not a bare repository")
+	}
+
+	remoteUrl, err := c.CliWrappers.GitCli.GetRemoteUrl(c.Params.MirrorDir, "origin")
+	if err != nil {
+		return err
+	}
+	if normalizeRepoUrl(remoteUrl) != normalizeRepoUrl(c.Params.RepoUrl) {
+		return fmt.Errorf("mirror has different remote '%s'", remoteUrl)
+	}
+
+	fetchArgs := &cliWrappers.GitFetchArgs{
+		RepoDir: c.Params.MirrorDir,
+		Remote:  "origin",
+		Prune:   true,
+	}
+	return c.CliWrappers.GitCli.Fetch(fetchArgs)
+}
+
+func (c *GitMirrorUpdate) validateParams() error {
+	if c.Params.RepoUrl == "" {
+		return errors.New("git repository url must be set")
+	}
+	if isOptionLike(c.Params.RepoUrl) {
+		return fmt.Errorf("repository url '%s' must not start with '-' or contain whitespace", c.Params.RepoUrl)
+	}
+	if !strings.HasPrefix(c.Params.RepoUrl, "https://") && !strings.HasPrefix(c.Params.RepoUrl, "file://") && !isSshUrl(c.Params.RepoUrl) {
+		return errors.New("only https, ssh and file protocols are supported")
+	}
+	if c.Params.MirrorDir == "" {
+		return errors.New("mirror directory must be set")
+	}
+	if strings.HasPrefix(c.Params.MirrorDir, "-") {
+		return fmt.Errorf("mirror dir '%s' is invalid", c.Params.MirrorDir)
+	}
+	return validateNetworkConfig(c.Params.networkConfig())
+}
@@ -0,0 +1,172 @@
+package commands_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+	"github.com/mmorhun/konflux-task-cli/pkg/commands"
+)
+
+const mirrorRepoUrl = "file:///cache/remote.git"
+
+func setupTestGitMirrorUpdate(mockGitCli *MockGitCli, mirrorDir string) *commands.GitMirrorUpdate {
+	return &commands.GitMirrorUpdate{
+		Params: &commands.GitMirrorUpdateParams{
+			RepoUrl:   mirrorRepoUrl,
+			MirrorDir: mirrorDir,
+		},
+		CliWrappers: commands.GitMirrorUpdateCliWrappers{
+			GitCli: mockGitCli,
+		},
+	}
+}
+
+func TestGitMirrorUpdate_Create(t *testing.T) {
+	g := NewWithT(t)
+
+	mirrorDir := t.TempDir()
+	mockGitCli := &MockGitCli{}
+	gitMirrorUpdate := setupTestGitMirrorUpdate(mockGitCli, mirrorDir)
+
+	isCloned := false
+	mockGitCli.CloneFunc = func(args *cliwrappers.GitCloneArgs) (string, error) {
+		g.Expect(args.Url).To(Equal(mirrorRepoUrl))
+		g.Expect(args.Directory).To(Equal(mirrorDir))
+		g.Expect(args.Mirror).To(BeTrue())
+		isCloned = true
+		return args.Directory, nil
+	}
+	mockGitCli.FetchFunc = func(args *cliwrappers.GitFetchArgs) error {
+		g.Fail("fetch without existing mirror")
+		return nil
+	}
+
+	err := gitMirrorUpdate.Run()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(isCloned).To(BeTrue())
+}
+
+func TestGitMirrorUpdate_Update(t *testing.T) {
+	g := NewWithT(t)
+
+	mirrorDir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(mirrorDir, "HEAD"), []byte("ref: refs/heads/main\n"), 0644)).To(Succeed())
+	mockGitCli := &MockGitCli{}
+	gitMirrorUpdate := setupTestGitMirrorUpdate(mockGitCli, mirrorDir)
+
+	isFetched := false
+	mockGitCli.GetRemoteUrlFunc = func(repoDir, remote string) (string, error) {
+		g.Expect(repoDir).To(Equal(mirrorDir))
+		return mirrorRepoUrl, nil
+	}
+	mockGitCli.FetchFunc = func(args *cliwrappers.GitFetchArgs) error {
+		g.Expect(args.RepoDir).To(Equal(mirrorDir))
+		g.Expect(args.Remote).To(Equal("origin"))
+		g.Expect(args.Refspecs).To(BeEmpty())
+		g.Expect(args.Prune).To(BeTrue())
+		isFetched = true
+		return nil
+	}
+	mockGitCli.CloneFunc = func(args *cliwrappers.GitCloneArgs) (string, error) {
+		g.Fail("clone of existing mirror")
+		return "", nil
+	}
+
+	err := gitMirrorUpdate.Run()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(isFetched).To(BeTrue())
+	g.Expect(filepath.Join(mirrorDir, "HEAD")).To(BeARegularFile())
+}
+
+func TestGitMirrorUpdate_RecreateMirrorOfDifferentRepository(t *testing.T) {
+	g := NewWithT(t)
+
+	mirrorDir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(mirrorDir, "HEAD"), []byte("ref: refs/heads/main\n"), 0644)).To(Succeed())
+	mockGitCli := &MockGitCli{}
+	gitMirrorUpdate := setupTestGitMirrorUpdate(mockGitCli, mirrorDir)
+
+	isCloned := false
+	mockGitCli.GetRemoteUrlFunc = func(repoDir, remote string) (string, error) {
+		return "file:///cache/other.git", nil
+	}
+	mockGitCli.CloneFunc = func(args *cliwrappers.GitCloneArgs) (string, error) {
+		g.Expect(filepath.Join(mirrorDir, "HEAD")).ToNot(BeAnExistingFile())
+		isCloned = true
+		return args.Directory, nil
+	}
+
+	err := gitMirrorUpdate.Run()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(isCloned).To(BeTrue())
+}
+
+func TestGitMirrorUpdate_RecreateNonBareRepository(t *testing.T) {
+	g := NewWithT(t)
+
+	mirrorDir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(mirrorDir, "HEAD"), []byte("ref: refs/heads/main\n"), 0644)).To(Succeed())
+	mockGitCli := &MockGitCli{}
+	gitMirrorUpdate := setupTestGitMirrorUpdate(mockGitCli, mirrorDir)
+
+	isCloned := false
+	mockGitCli.IsBareRepositoryFunc = func(repoDir string) (bool, error) {
+		return false, nil
+	}
+	mockGitCli.CloneFunc = func(args *cliwrappers.GitCloneArgs) (string, error) {
+		isCloned = true
+		return args.Directory, nil
+	}
+
+	err := gitMirrorUpdate.Run()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(isCloned).To(BeTrue())
+}
+
+func TestGitMirrorUpdate_CloneError(t *testing.T) {
+	g := NewWithT(t)
+
+	mockGitCli := &MockGitCli{}
+	gitMirrorUpdate := setupTestGitMirrorUpdate(mockGitCli, t.TempDir())
+
+	mockGitCli.CloneFunc = func(args *cliwrappers.GitCloneArgs) (string, error) {
+		return "", errors.New("git clone failed: exit status 128")
+	}
+
+	err := gitMirrorUpdate.Run()
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to create mirror"))
+}
+
+func TestGitMirrorUpdate_InvalidUrl(t *testing.T) {
+	g := NewWithT(t)
+
+	mockGitCli := &MockGitCli{}
+	gitMirrorUpdate := setupTestGitMirrorUpdate(mockGitCli, t.TempDir())
+	gitMirrorUpdate.Params.RepoUrl = "http://github.com/test/repo.git"
+
+	err := gitMirrorUpdate.Run()
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("only https, ssh and file protocols are supported"))
+}
+
+func TestGitMirrorUpdate_OptionLikeUrl(t *testing.T) {
+	g := NewWithT(t)
+
+	mockGitCli := &MockGitCli{}
+	mockGitCli.CloneFunc = func(args *cliwrappers.GitCloneArgs) (string, error) {
+		g.Fail("clone must not be called")
+		return "", nil
+	}
+	gitMirrorUpdate := setupTestGitMirrorUpdate(mockGitCli, t.TempDir())
+	gitMirrorUpdate.Params.RepoUrl = "--upload-pack=sh -c 'id>pwned';:x"
+
+	err := gitMirrorUpdate.Run()
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("must not start with '-' or contain whitespace"))
+}
@@ -1,9 +1,14 @@
 package commands
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 
 	cliWrappers "github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
@@ -21,6 +26,9 @@ import (
 // )
 // var GitCloneParamsInfo = map[GitCloneParamName]common.Parameter{
 
+// partialCloneFilterRegex matches filter specs supported by git clone --filter
+var partialCloneFilterRegex = regexp.MustCompile(`^(blob:none|blob:limit=[0-9]+[kmg]?|tree:[0-9]+|object:type=(blob|tree|commit|tag)|sparse:oid=[^\s]+|combine:[^\s]+)$`)
+
 var GitCloneParamsConfig = map[string]common.Parameter{
 	"url": {
 		Name:       "url",
@@ -30,13 +38,19 @@ var GitCloneParamsConfig = map[string]common.Parameter{
 		Required:   true,
 	},
 	"branch": {
-		Name:         "branch",
-		ShortName:    "b",
-		EnvVarName:   "GIT_BRANCH",
-		TypeKind:     reflect.String,
-		DefaultValue: "main",
-		Usage:        "Branch to clone from",
-		Required:     false,
+		Name:       "branch",
+		ShortName:  "b",
+		EnvVarName: "GIT_BRANCH",
+		TypeKind:   reflect.String,
+		Usage:      "Branch to clone from, the remote default branch if not set",
+		Required:   false,
+	},
+	"revision": {
+		Name:       "revision",
+		ShortName:  "r",
+		EnvVarName: "GIT_REVISION",
+		TypeKind:   reflect.String,
+		Usage:      "Revision to checkout: full commit SHA, tag or refspec, e.g. refs/pull/123/head. Takes precedence over branch",
 	},
 	"depth": {
 		Name:         "depth",
@@ -45,6 +59,219 @@ var GitCloneParamsConfig = map[string]common.Parameter{
 		DefaultValue: "",
 		Usage:        "Clone depth",
 	},
+	"output-dir": {
+		Name:       "output-dir",
+		ShortName:  "o",
+		EnvVarName: "GIT_OUTPUT_DIR",
+		TypeKind:   reflect.String,
+		Usage:      "Directory to clone into, derived from the repository url if not set",
+	},
+	"delete-existing": {
+		Name:         "delete-existing",
+		EnvVarName:   "GIT_DELETE_EXISTING",
+		TypeKind:     reflect.Bool,
+		DefaultValue: "false",
+		Usage:        "Delete content of the output directory before clone",
+	},
+	"update-existing": {
+		Name:         "update-existing",
+		EnvVarName:   "GIT_UPDATE_EXISTING",
+		TypeKind:     reflect.Bool,
+		DefaultValue: "false",
+		Usage:        "Update existing clone of the same repository in the output directory instead of cloning from scratch",
+	},
+	"reference-dir": {
+		Name:       "reference-dir",
+		EnvVarName: "GIT_REFERENCE_DIR",
+		TypeKind:   reflect.String,
+		Usage:      "Local repository, e.g. a bare mirror, to borrow objects from if it's usable",
+	},
+	"dissociate": {
+		Name:         "dissociate",
+		EnvVarName:   "GIT_DISSOCIATE",
+		TypeKind:     reflect.Bool,
+		DefaultValue: "false",
+		Usage:        "Copy objects borrowed from the reference directory, so the clone doesn't depend on it",
+	},
+	"base-ref": {
+		Name:       "base-ref",
+		EnvVarName: "GIT_BASE_REF",
+		TypeKind:   reflect.String,
+		Usage:      "Branch or commit to compute the list of changed files against",
+	},
+	"watch-paths": {
+		Name:         "watch-paths",
+		EnvVarName:   "GIT_WATCH_PATHS",
+		TypeKind:     reflect.Array,
+		DefaultValue: "",
+		Usage:        "Glob patterns of paths to check for changes against the base ref",
+	},
+	"merge-target-branch": {
+		Name:       "merge-target-branch",
+		EnvVarName: "GIT_MERGE_TARGET_BRANCH",
+		TypeKind:   reflect.String,
+		Usage:      "Branch to merge into the checked out revision, e.g. for pre-merge builds",
+	},
+	"merge-sha": {
+		Name:       "merge-sha",
+		EnvVarName: "GIT_MERGE_SHA",
+		TypeKind:   reflect.String,
+		Usage:      "Commit of the merge target branch to merge, the target branch head if not set",
+	},
+	"sparse-paths": {
+		Name:         "sparse-paths",
+		EnvVarName:   "GIT_SPARSE_PATHS",
+		TypeKind:     reflect.Array,
+		DefaultValue: "",
+		Usage:        "Directories to checkout using cone mode sparse checkout, whole tree if not set",
+	},
+	"filter": {
+		Name:       "filter",
+		EnvVarName: "GIT_FILTER",
+		TypeKind:   reflect.String,
+		Usage:      "Partial clone filter, e.g. blob:none or tree:0",
+	},
+	"submodules": {
+		Name:         "submodules",
+		EnvVarName:   "GIT_SUBMODULES",
+		TypeKind:     reflect.String,
+		DefaultValue: submodulesModeOff,
+		Usage:        "Submodules checkout mode: off, top-level or recursive",
+	},
+	"submodules-depth": {
+		Name:         "submodules-depth",
+		EnvVarName:   "GIT_SUBMODULES_DEPTH",
+		TypeKind:     reflect.Int,
+		DefaultValue: "",
+		Usage:        "Submodules clone depth, full history if not set",
+	},
+	"submodule-paths": {
+		Name:         "submodule-paths",
+		EnvVarName:   "GIT_SUBMODULE_PATHS",
+		TypeKind:     reflect.Array,
+		DefaultValue: "",
+		Usage:        "Paths of submodules to checkout, all submodules if not set",
+	},
+	"lfs": {
+		Name:         "lfs",
+		EnvVarName:   "GIT_LFS",
+		TypeKind:     reflect.Bool,
+		DefaultValue: "false",
+		Usage:        "Fetch Git LFS objects after checkout. Requires git-lfs installed",
+	},
+	"lfs-include": {
+		Name:         "lfs-include",
+		EnvVarName:   "GIT_LFS_INCLUDE",
+		TypeKind:     reflect.Array,
+		DefaultValue: "",
+		Usage:        "Paths or patterns of LFS objects to fetch, all if not set",
+	},
+	"lfs-exclude": {
+		Name:         "lfs-exclude",
+		EnvVarName:   "GIT_LFS_EXCLUDE",
+		TypeKind:     reflect.Array,
+		DefaultValue: "",
+		Usage:        "Paths or patterns of LFS objects to skip",
+	},
+	"basic-auth-directory": {
+		Name:       "basic-auth-directory",
+		EnvVarName: "BASIC_AUTH_DIRECTORY",
+		TypeKind:   reflect.String,
+		Usage:      "Path to directory with .git-credentials and .gitconfig files (Tekton basic-auth workspace)",
+	},
+	"token-file": {
+		Name:       "token-file",
+		EnvVarName: "GIT_TOKEN_FILE",
+		TypeKind:   reflect.String,
+		Usage:      "Path to file with access token for https repositories",
+	},
+	"token-username": {
+		Name:         "token-username",
+		EnvVarName:   "GIT_TOKEN_USERNAME",
+		TypeKind:     reflect.String,
+		DefaultValue: "git",
+		Usage:        "Username to use together with the access token",
+	},
+	"ssh-directory": {
+		Name:       "ssh-directory",
+		EnvVarName: "SSH_DIRECTORY",
+		TypeKind:   reflect.String,
+		Usage:      "Path to directory with SSH private keys, known_hosts and config files (Tekton ssh-directory workspace)",
+	},
+	"ssh-skip-host-key-verification": {
+		Name:         "ssh-skip-host-key-verification",
+		EnvVarName:   "SSH_SKIP_HOST_KEY_VERIFICATION",
+		TypeKind:     reflect.Bool,
+		DefaultValue: "false",
+		Usage:        "Disables SSH host key verification. Insecure, use known_hosts file instead",
+	},
+	"verify-signature": {
+		Name:         "verify-signature",
+		EnvVarName:   "GIT_VERIFY_SIGNATURE",
+		TypeKind:     reflect.Bool,
+		DefaultValue: "false",
+		Usage:        "Fail if the checked out commit is not signed by a trusted GPG or SSH key",
+	},
+	"gpg-keyring": {
+		Name:       "gpg-keyring",
+		EnvVarName: "GIT_GPG_KEYRING",
+		TypeKind:   reflect.String,
+		Usage:      "Path to file with trusted GPG public keys, armored or binary, for signature verification",
+	},
+	"ssh-allowed-signers": {
+		Name:       "ssh-allowed-signers",
+		EnvVarName: "GIT_SSH_ALLOWED_SIGNERS",
+		TypeKind:   reflect.String,
+		Usage:      "Path to SSH allowed signers file with trusted keys for signature verification",
+	},
+	"url-policy-file": {
+		Name:       "url-policy-file",
+		EnvVarName: "GIT_URL_POLICY_FILE",
+		TypeKind:   reflect.String,
+		Usage:      "Path to JSON file with allowed and denied git hosts and paths",
+	},
+	"archive-format": {
+		Name:       "archive-format",
+		EnvVarName: "GIT_ARCHIVE_FORMAT",
+		TypeKind:   reflect.String,
+		Usage:      "Format of the checked out sources archive: tar.gz or git-bundle",
+	},
+	"archive-path": {
+		Name:       "archive-path",
+		EnvVarName: "GIT_ARCHIVE_PATH",
+		TypeKind:   reflect.String,
+		Usage:      "Path of the checked out sources archive file",
+	},
+	"chown": {
+		Name:       "chown",
+		EnvVarName: "GIT_CHOWN",
+		TypeKind:   reflect.String,
+		Usage:      "Owner to set on the cloned sources in numeric uid[:gid] form",
+	},
+	"chmod-mode": {
+		Name:       "chmod-mode",
+		EnvVarName: "GIT_CHMOD_MODE",
+		TypeKind:   reflect.String,
+		Usage:      "Octal permissions to set on the cloned sources, execute bits are kept only for executable files",
+	},
+	"manifest": {
+		Name:       "manifest",
+		EnvVarName: "GIT_MANIFEST",
+		TypeKind:   reflect.String,
+		Usage:      "Path to YAML or JSON list of additional repositories to clone with url, revision, path and depth",
+	},
+	"manifest-parallelism": {
+		Name:         "manifest-parallelism",
+		EnvVarName:   "GIT_MANIFEST_PARALLELISM",
+		TypeKind:     reflect.Int,
+		DefaultValue: "4",
+		Usage:        "Maximum number of manifest repositories cloned at once",
+	},
+	"http-proxy":  httpProxyParam,
+	"https-proxy": httpsProxyParam,
+	"no-proxy":    noProxyParam,
+	"ca-bundle":   caBundleParam,
+	"git-backend": gitBackendParam,
 	"verbose": {
 		Name:         "verbose",
 		ShortName:    "v",
@@ -56,10 +283,46 @@ var GitCloneParamsConfig = map[string]common.Parameter{
 }
 
 type GitCloneParams struct {
-	RepoUrl string `paramName:"url"`
-	Branch  string `paramName:"branch"`
-	Depth   int    `paramName:"depth"`
-	Verbose bool   `paramName:"verbose"`
+	NetworkParams
+
+	RepoUrl                    string   `paramName:"url"`
+	Branch                     string   `paramName:"branch"`
+	Revision                   string   `paramName:"revision"`
+	Depth                      int      `paramName:"depth"`
+	OutputDir                  string   `paramName:"output-dir"`
+	DeleteExisting             bool     `paramName:"delete-existing"`
+	UpdateExisting             bool     `paramName:"update-existing"`
+	ReferenceDir               string   `paramName:"reference-dir"`
+	Dissociate                 bool     `paramName:"dissociate"`
+	BaseRef                    string   `paramName:"base-ref"`
+	WatchPaths                 []string `paramName:"watch-paths"`
+	MergeTargetBranch          string   `paramName:"merge-target-branch"`
+	MergeSha                   string   `paramName:"merge-sha"`
+	SparsePaths                []string `paramName:"sparse-paths"`
+	Filter                     string   `paramName:"filter"`
+	Submodules                 string   `paramName:"submodules"`
+	SubmodulesDepth            int      `paramName:"submodules-depth"`
+	SubmodulePaths             []string `paramName:"submodule-paths"`
+	Lfs                        bool     `paramName:"lfs"`
+	LfsInclude                 []string `paramName:"lfs-include"`
+	LfsExclude                 []string `paramName:"lfs-exclude"`
+	BasicAuthDirectory         string   `paramName:"basic-auth-directory"`
+	TokenFile                  string   `paramName:"token-file"`
+	TokenUsername              string   `paramName:"token-username"`
+	SshDirectory               string   `paramName:"ssh-directory"`
+	SshSkipHostKeyVerification bool     `paramName:"ssh-skip-host-key-verification"`
+	VerifySignature            bool     `paramName:"verify-signature"`
+	GpgKeyring                 string   `paramName:"gpg-keyring"`
+	SshAllowedSigners          string   `paramName:"ssh-allowed-signers"`
+	UrlPolicyFile              string   `paramName:"url-policy-file"`
+	ArchiveFormat              string   `paramName:"archive-format"`
+	ArchivePath                string   `paramName:"archive-path"`
+	Chown                      string   `paramName:"chown"`
+	ChmodMode                  string   `paramName:"chmod-mode"`
+	Manifest                   string   `paramName:"manifest"`
+	ManifestParallelism        int      `paramName:"manifest-parallelism"`
+	GitBackend                 string   `paramName:"git-backend"`
+	Verbose                    bool     `paramName:"verbose"`
 }
 
 type GitCloneResultFilesPath struct {
@@ -67,10 +330,35 @@ type GitCloneResultFilesPath struct {
 	SourceDir   string `env:"RESULT_SOURCE_DIR"`
 	Commit      string `env:"RESULT_COMMIT"`
 	ShortCommit string `env:"RESULT_SHORT_COMMIT"`
+	Branch      string `env:"RESULT_BRANCH,optional"`
+	Submodules  string `env:"RESULT_SUBMODULES,optional"`
+	// Absolute path of the source dir, e.g. inside the shared workspace
+	AbsoluteSourceDir string `env:"RESULT_ABSOLUTE_SOURCE_DIR,optional"`
+	// Pre-merge build commits
+	OriginalCommit string `env:"RESULT_ORIGINAL_COMMIT,optional"`
+	MergeCommit    string `env:"RESULT_MERGE_COMMIT,optional"`
+	// Commit metadata
+	CommitAuthor    string `env:"RESULT_COMMIT_AUTHOR,optional"`
+	CommitTimestamp string `env:"RESULT_COMMIT_TIMESTAMP,optional"`
+	CommitDate      string `env:"RESULT_COMMIT_DATE,optional"`
+	CommitMessage   string `env:"RESULT_COMMIT_MESSAGE,optional"`
+	CommitDescribe  string `env:"RESULT_COMMIT_DESCRIBE,optional"`
+
+	CommitSigner string `env:"RESULT_COMMIT_SIGNER,optional"`
+
+	ChangedFiles        string `env:"RESULT_CHANGED_FILES,optional"`
+	WatchedPathsChanged string `env:"RESULT_WATCHED_PATHS_CHANGED,optional"`
+
+	// JSON list of cloned manifest repositories with their commits
+	Manifest string `env:"RESULT_MANIFEST,optional"`
+
+	ArchivePath   string `env:"RESULT_ARCHIVE_PATH,optional"`
+	ArchiveSha256 string `env:"RESULT_ARCHIVE_SHA256,optional"`
 }
 
 type GitCloneCliWrappers struct {
 	GitCli cliWrappers.GitCliInterface
+	GpgCli cliWrappers.GpgCliInterface
 }
 
 type GitClone struct {
@@ -78,6 +366,8 @@ type GitClone struct {
 	Results       *GitCloneResultFilesPath
 	ResultsWriter common.ResultsWriterInterface
 	CliWrappers   GitCloneCliWrappers
+
+	urlPolicy *UrlPolicy
 }
 
 func NewGitClone(cmd *cobra.Command) (*GitClone, error) {
@@ -106,32 +396,319 @@ func NewGitClone(cmd *cobra.Command) (*GitClone, error) {
 func (c *GitClone) initCliWrappers() error {
 	executor := cliWrappers.NewCliExecutor(c.Params.Verbose)
 
-	gitCli, err := cliWrappers.NewGitCli(executor, c.Params.Verbose)
+	gitCli, err := newNetworkGitBackend(c.Params.GitBackend, c.Params.networkConfig(), c.Params.Verbose)
 	if err != nil {
 		return err
 	}
 	c.CliWrappers.GitCli = gitCli
+
+	if c.Params.VerifySignature && c.Params.GpgKeyring != "" {
+		gpgCli, err := cliWrappers.NewGpgCli(executor, c.Params.Verbose)
+		if err != nil {
+			return err
+		}
+		c.CliWrappers.GpgCli = gpgCli
+	}
 	return nil
 }
 
 func (c *GitClone) Run() error {
 	if c.Params.Verbose {
 		l.Logger.Infof("[param] repository: %s", c.Params.RepoUrl)
+		if c.Params.GitBackend != "" {
+			l.Logger.Infof("[param] git backend: %s", c.Params.GitBackend)
+		}
+		if c.Params.UrlPolicyFile != "" {
+			l.Logger.Infof("[param] url policy file: %s", c.Params.UrlPolicyFile)
+		}
 		if c.Params.Branch != "" {
 			l.Logger.Infof("[param] branch: %s", c.Params.Branch)
 		}
+		if c.Params.Revision != "" {
+			l.Logger.Infof("[param] revision: %s", c.Params.Revision)
+		}
 		if c.Params.Depth > 0 {
 			l.Logger.Infof("[param] depth: %d", c.Params.Depth)
 		}
+		if c.Params.OutputDir != "" {
+			l.Logger.Infof("[param] output dir: %s", c.Params.OutputDir)
+		}
+		if c.Params.DeleteExisting {
+			l.Logger.Info("[param] delete existing: true")
+		}
+		if c.Params.UpdateExisting {
+			l.Logger.Info("[param] update existing: true")
+		}
+		if c.Params.ReferenceDir != "" {
+			l.Logger.Infof("[param] reference dir: %s", c.Params.ReferenceDir)
+		}
+		if c.Params.Dissociate {
+			l.Logger.Info("[param] dissociate: true")
+		}
+		if c.Params.BaseRef != "" {
+			l.Logger.Infof("[param] base ref: %s", c.Params.BaseRef)
+		}
+		if len(c.Params.WatchPaths) > 0 {
+			l.Logger.Infof("[param] watch paths: %s", strings.Join(c.Params.WatchPaths, ", "))
+		}
+		if c.Params.MergeTargetBranch != "" {
+			l.Logger.Infof("[param] merge target branch: %s", c.Params.MergeTargetBranch)
+		}
+		if c.Params.MergeSha != "" {
+			l.Logger.Infof("[param] merge sha: %s", c.Params.MergeSha)
+		}
+		if len(c.Params.SparsePaths) > 0 {
+			l.Logger.Infof("[param] sparse paths: %s", strings.Join(c.Params.SparsePaths, ", "))
+		}
+		if c.Params.Filter != "" {
+			l.Logger.Infof("[param] filter: %s", c.Params.Filter)
+		}
+		if c.Params.Submodules != "" && c.Params.Submodules != submodulesModeOff {
+			l.Logger.Infof("[param] submodules: %s", c.Params.Submodules)
+		}
+		if c.Params.SubmodulesDepth > 0 {
+			l.Logger.Infof("[param] submodules depth: %d", c.Params.SubmodulesDepth)
+		}
+		if len(c.Params.SubmodulePaths) > 0 {
+			l.Logger.Infof("[param] submodule paths: %s", strings.Join(c.Params.SubmodulePaths, ", "))
+		}
+		if c.Params.Lfs {
+			l.Logger.Info("[param] lfs: enabled")
+		}
+		if len(c.Params.LfsInclude) > 0 {
+			l.Logger.Infof("[param] lfs include: %s", strings.Join(c.Params.LfsInclude, ", "))
+		}
+		if len(c.Params.LfsExclude) > 0 {
+			l.Logger.Infof("[param] lfs exclude: %s", strings.Join(c.Params.LfsExclude, ", "))
+		}
+		if c.Params.BasicAuthDirectory != "" {
+			l.Logger.Infof("[param] basic auth directory: %s", c.Params.BasicAuthDirectory)
+		}
+		if c.Params.TokenFile != "" {
+			l.Logger.Infof("[param] token file: %s", c.Params.TokenFile)
+		}
+		if c.Params.SshDirectory != "" {
+			l.Logger.Infof("[param] ssh directory: %s", c.Params.SshDirectory)
+		}
+		if c.Params.SshSkipHostKeyVerification {
+			l.Logger.Info("[param] ssh host key verification: disabled")
+		}
+		if c.Params.VerifySignature {
+			l.Logger.Info("[param] verify signature: true")
+		}
+		if c.Params.GpgKeyring != "" {
+			l.Logger.Infof("[param] gpg keyring: %s", c.Params.GpgKeyring)
+		}
+		if c.Params.SshAllowedSigners != "" {
+			l.Logger.Infof("[param] ssh allowed signers: %s", c.Params.SshAllowedSigners)
+		}
+		if c.Params.ArchiveFormat != "" {
+			l.Logger.Infof("[param] archive format: %s", c.Params.ArchiveFormat)
+			l.Logger.Infof("[param] archive path: %s", c.Params.ArchivePath)
+		}
+		if c.Params.Chown != "" {
+			l.Logger.Infof("[param] chown: %s", c.Params.Chown)
+		}
+		if c.Params.ChmodMode != "" {
+			l.Logger.Infof("[param] chmod mode: %s", c.Params.ChmodMode)
+		}
+		if c.Params.Manifest != "" {
+			l.Logger.Infof("[param] manifest: %s", c.Params.Manifest)
+			l.Logger.Infof("[param] manifest parallelism: %d", c.Params.ManifestParallelism)
+		}
+		logNetworkConfig(c.Params.networkConfig())
+	}
+
+	if c.Params.UrlPolicyFile != "" {
+		urlPolicy, err := loadUrlPolicy(c.Params.UrlPolicyFile)
+		if err != nil {
+			return err
+		}
+		c.urlPolicy = urlPolicy
 	}
 
 	if err := c.validateParams(); err != nil {
 		return err
 	}
 
-	sourceDir, err := c.CliWrappers.GitCli.Clone(c.Params.RepoUrl, c.Params.Branch, c.Params.Depth)
+	var manifestEntries []GitManifestEntry
+	if c.Params.Manifest != "" {
+		entries, err := loadManifest(c.Params.Manifest)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := validateRepoUrl(entry.Url); err != nil {
+				return fmt.Errorf("manifest repository '%s' url is invalid: %w", entry.Path, err)
+			}
+			if err := checkUrlPolicy(entry.Url, c.urlPolicy); err != nil {
+				return fmt.Errorf("manifest repository '%s' is not allowed: %w", entry.Path, err)
+			}
+		}
+		manifestEntries = entries
+	}
+
+	if c.Params.Lfs {
+		if !c.CliWrappers.GitCli.IsLfsAvailable() {
+			return errors.New("lfs parameter is set, but git-lfs is not installed")
+		}
+		// Do not download LFS objects during checkout, they are fetched afterwards according to filters.
+		c.CliWrappers.GitCli.SetEnv("GIT_LFS_SKIP_SMUDGE", "1")
+	}
+
+	// Manifest repositories are cloned concurrently, so credentials for all of them are configured upfront.
+	usesSsh, usesHttps := isSshUrl(c.Params.RepoUrl), !isSshUrl(c.Params.RepoUrl)
+	for _, entry := range manifestEntries {
+		if isSshUrl(entry.Url) {
+			usesSsh = true
+		} else {
+			usesHttps = true
+		}
+	}
+
+	if c.isHttpsAuthConfigured() && usesHttps {
+		cleanup, err := c.setupHttpsAuth()
+		if err != nil {
+			return fmt.Errorf("failed to configure credentials: %w", err)
+		}
+		defer cleanup()
+	}
+
+	if usesSsh {
+		cleanup, err := c.setupSsh()
+		if err != nil {
+			return fmt.Errorf("failed to configure ssh: %w", err)
+		}
+		defer cleanup()
+	}
+
+	if c.Params.VerifySignature {
+		cleanup, err := c.setupSignatureVerification()
+		if err != nil {
+			return fmt.Errorf("failed to configure signature verification: %w", err)
+		}
+		defer cleanup()
+	}
+
+	if c.Params.Branch == "" && c.Params.Revision == "" {
+		branch, err := c.CliWrappers.GitCli.GetRemoteDefaultBranch(c.Params.RepoUrl)
+		if err != nil {
+			return fmt.Errorf("failed to detect remote default branch: %w", err)
+		}
+		l.Logger.Infof("Using remote default branch '%s'", branch)
+		c.Params.Branch = branch
+	}
+
+	sourceDir := c.Params.OutputDir
+	if sourceDir == "" {
+		sourceDir = getRepoDirName(c.Params.RepoUrl)
+	}
+	absoluteSourceDir, err := filepath.Abs(sourceDir)
+	if err != nil {
+		return err
+	}
+	// The existing clone or the sources after chown might be owned by another user.
+	// Trust them for git commands of this step only, without modifying global git config.
+	c.CliWrappers.GitCli.AddConfig("safe.directory", absoluteSourceDir)
+
+	if len(manifestEntries) > 0 {
+		if err := checkManifestPaths(manifestEntries, sourceDir); err != nil {
+			return err
+		}
+		for _, entry := range manifestEntries {
+			absoluteEntryPath, err := filepath.Abs(entry.Path)
+			if err != nil {
+				return err
+			}
+			c.CliWrappers.GitCli.AddConfig("safe.directory", absoluteEntryPath)
+		}
+	}
+
+	if c.Params.DeleteExisting {
+		if err := common.RemoveDirContent(sourceDir); err != nil {
+			return fmt.Errorf("failed to delete existing directory content: %w", err)
+		}
+	}
+
+	isUpdated := false
+	if c.Params.UpdateExisting {
+		isUpdated = c.updateExistingClone(sourceDir)
+	}
+
+	if !isUpdated {
+		if c.Params.Revision != "" {
+			sourceDir, err = c.fetchRevision(sourceDir)
+			if err != nil {
+				return fmt.Errorf("failed to fetch revision '%s': %w", c.Params.Revision, err)
+			}
+		} else {
+			sourceDir, err = c.clone(sourceDir)
+			if err != nil {
+				return fmt.Errorf("git clone failed: %w", err)
+			}
+		}
+	}
+
+	var commitSigner string
+	if c.Params.VerifySignature {
+		commitSigner, err = c.verifyCommitSignature(sourceDir)
+		if err != nil {
+			return fmt.Errorf("signature verification failed: %w", err)
+		}
+	}
+
+	var originalCommitSha string
+	if c.isMergeRequested() {
+		originalCommitSha, err = c.mergeTargetBranch(sourceDir)
+		if err != nil {
+			return fmt.Errorf("failed to merge target branch: %w", err)
+		}
+	}
+
+	var changedFiles []string
+	if c.Params.BaseRef != "" {
+		changedFiles, err = c.getChangedFiles(sourceDir)
+		if err != nil {
+			return fmt.Errorf("failed to get changed files: %w", err)
+		}
+	}
+
+	if c.Params.Lfs {
+		if err := c.pullLfsObjects(sourceDir); err != nil {
+			return fmt.Errorf("failed to fetch lfs objects: %w", err)
+		}
+	}
+
+	submodules, err := c.updateSubmodules(sourceDir)
 	if err != nil {
-		return fmt.Errorf("git clone failed: %w", err)
+		return fmt.Errorf("failed to update submodules: %w", err)
+	}
+
+	var manifestResults []GitManifestResult
+	if len(manifestEntries) > 0 {
+		manifestResults, err = c.cloneManifest(manifestEntries)
+		if err != nil {
+			return fmt.Errorf("failed to clone manifest repositories: %w", err)
+		}
+	}
+
+	var archiveSha256 string
+	if c.Params.ArchiveFormat != "" {
+		archiveSha256, err = c.createSourceArchive(sourceDir)
+		if err != nil {
+			return fmt.Errorf("failed to create sources archive: %w", err)
+		}
+	}
+
+	if c.Params.Chown != "" || c.Params.ChmodMode != "" {
+		if err := c.fixSourcesPermissions(sourceDir); err != nil {
+			return fmt.Errorf("failed to set sources ownership and permissions: %w", err)
+		}
+		for _, manifestResult := range manifestResults {
+			if err := c.fixSourcesPermissions(manifestResult.Path); err != nil {
+				return fmt.Errorf("failed to set sources ownership and permissions: %w", err)
+			}
+		}
 	}
 
 	commitSha, err := c.CliWrappers.GitCli.GetRepoHeadFullSha(sourceDir)
@@ -146,29 +723,299 @@ func (c *GitClone) Run() error {
 	if err := c.ResultsWriter.WriteResultString(sourceDir, c.Results.SourceDir); err != nil {
 		return err
 	}
+	if err := common.WriteOptionalResultString(c.ResultsWriter, absoluteSourceDir, c.Results.AbsoluteSourceDir); err != nil {
+		return err
+	}
 	if err := c.ResultsWriter.WriteResultString(commitSha, c.Results.Commit); err != nil {
 		return err
 	}
 	if err := c.ResultsWriter.WriteResultString(commitShortSha, c.Results.ShortCommit); err != nil {
 		return err
 	}
+	if err := common.WriteOptionalResultString(c.ResultsWriter, c.Params.Branch, c.Results.Branch); err != nil {
+		return err
+	}
+	submodulesJson, err := json.Marshal(submodules)
+	if err != nil {
+		return err
+	}
+	if err := common.WriteOptionalResultString(c.ResultsWriter, string(submodulesJson), c.Results.Submodules); err != nil {
+		return err
+	}
+
+	if err := c.writeCommitMetadataResults(sourceDir); err != nil {
+		return err
+	}
+	if c.Params.VerifySignature {
+		if err := common.WriteOptionalResultString(c.ResultsWriter, commitSigner, c.Results.CommitSigner); err != nil {
+			return err
+		}
+	}
+	if c.Params.BaseRef != "" {
+		changedFilesJson, err := json.Marshal(changedFiles)
+		if err != nil {
+			return err
+		}
+		if err := common.WriteOptionalResultString(c.ResultsWriter, string(changedFilesJson), c.Results.ChangedFiles); err != nil {
+			return err
+		}
+		if len(c.Params.WatchPaths) > 0 {
+			watchedPathsChanged := strconv.FormatBool(isAnyWatchedPathChanged(changedFiles, c.Params.WatchPaths))
+			if err := common.WriteOptionalResultString(c.ResultsWriter, watchedPathsChanged, c.Results.WatchedPathsChanged); err != nil {
+				return err
+			}
+		}
+	}
+	if c.isMergeRequested() {
+		if err := common.WriteOptionalResultString(c.ResultsWriter, originalCommitSha, c.Results.OriginalCommit); err != nil {
+			return err
+		}
+		if err := common.WriteOptionalResultString(c.ResultsWriter, commitSha, c.Results.MergeCommit); err != nil {
+			return err
+		}
+	}
+	if len(manifestResults) > 0 {
+		manifestJson, err := json.Marshal(manifestResults)
+		if err != nil {
+			return err
+		}
+		if err := common.WriteOptionalResultString(c.ResultsWriter, string(manifestJson), c.Results.Manifest); err != nil {
+			return err
+		}
+	}
+	if c.Params.ArchiveFormat != "" {
+		if err := common.WriteOptionalResultString(c.ResultsWriter, c.Params.ArchivePath, c.Results.ArchivePath); err != nil {
+			return err
+		}
+		if err := common.WriteOptionalResultString(c.ResultsWriter, archiveSha256, c.Results.ArchiveSha256); err != nil {
+			return err
+		}
+	}
 
 	if c.Params.Verbose {
 		l.Logger.Infof("[result] url: %s", c.Params.RepoUrl)
 		l.Logger.Infof("[result] source dir: %s", sourceDir)
+		l.Logger.Infof("[result] absolute source dir: %s", absoluteSourceDir)
 		l.Logger.Infof("[result] commit: %s", commitSha)
 		l.Logger.Infof("[result] short commit: %s", commitShortSha)
+		if c.Params.Branch != "" {
+			l.Logger.Infof("[result] branch: %s", c.Params.Branch)
+		}
+		if c.Params.VerifySignature {
+			l.Logger.Infof("[result] commit signer: %s", commitSigner)
+		}
+		if c.Params.BaseRef != "" {
+			l.Logger.Infof("[result] changed files: %d", len(changedFiles))
+			if len(c.Params.WatchPaths) > 0 {
+				l.Logger.Infof("[result] watched paths changed: %t", isAnyWatchedPathChanged(changedFiles, c.Params.WatchPaths))
+			}
+		}
+		if c.isMergeRequested() {
+			l.Logger.Infof("[result] original commit: %s", originalCommitSha)
+			l.Logger.Infof("[result] merge commit: %s", commitSha)
+		}
+		for _, manifestResult := range manifestResults {
+			l.Logger.Infof("[result] manifest repository: %s %s %s", manifestResult.Path, manifestResult.Url, manifestResult.Commit)
+		}
+		if c.Params.ArchiveFormat != "" {
+			l.Logger.Infof("[result] archive path: %s", c.Params.ArchivePath)
+			l.Logger.Infof("[result] archive sha256: %s", archiveSha256)
+		}
+		for _, submodule := range submodules {
+			l.Logger.Infof("[result] submodule: %s %s %s", submodule.Path, submodule.Url, submodule.Commit)
+		}
 	}
 
 	return nil
 }
 
+// clone clones the requested branch of the repository.
+// Returns the repository directory.
+func (c *GitClone) clone(repoDir string) (string, error) {
+	isSparse := len(c.Params.SparsePaths) > 0
+	cloneArgs := &cliWrappers.GitCloneArgs{
+		Url:        c.Params.RepoUrl,
+		Branch:     c.Params.Branch,
+		Depth:      c.Params.Depth,
+		Directory:  repoDir,
+		Filter:     c.Params.Filter,
+		Sparse:     isSparse,
+		Reference:  c.Params.ReferenceDir,
+		Dissociate: c.Params.Dissociate,
+	}
+	repoDir, err := c.CliWrappers.GitCli.Clone(cloneArgs)
+	if err != nil {
+		return "", err
+	}
+
+	if isSparse {
+		if err := c.CliWrappers.GitCli.SparseCheckoutSet(repoDir, c.Params.SparsePaths); err != nil {
+			return "", err
+		}
+	}
+
+	return repoDir, nil
+}
+
+// fetchRevision initializes a new repository and fetches only the requested revision.
+// Unlike clone, it allows to checkout any commit or ref, also with limited depth.
+// Returns the repository directory.
+func (c *GitClone) fetchRevision(repoDir string) (string, error) {
+	if err := c.CliWrappers.GitCli.Init(repoDir); err != nil {
+		return "", err
+	}
+	if err := c.CliWrappers.GitCli.AddRemote(repoDir, "origin", c.Params.RepoUrl); err != nil {
+		return "", err
+	}
+	if c.Params.ReferenceDir != "" {
+		if err := addReferenceAlternates(repoDir, c.Params.ReferenceDir); err != nil {
+			return "", err
+		}
+	}
+	fetchArgs := &cliWrappers.GitFetchArgs{
+		RepoDir:  repoDir,
+		Remote:   "origin",
+		Refspecs: []string{c.Params.Revision},
+		Depth:    c.Params.Depth,
+		Filter:   c.Params.Filter,
+	}
+	if err := c.CliWrappers.GitCli.Fetch(fetchArgs); err != nil {
+		return "", err
+	}
+	// Configure sparse checkout before checkout to avoid populating the whole working tree.
+	if len(c.Params.SparsePaths) > 0 {
+		if err := c.CliWrappers.GitCli.SparseCheckoutSet(repoDir, c.Params.SparsePaths); err != nil {
+			return "", err
+		}
+	}
+	if err := c.CliWrappers.GitCli.Checkout(repoDir, "FETCH_HEAD"); err != nil {
+		return "", err
+	}
+	if c.Params.ReferenceDir != "" && c.Params.Dissociate {
+		if err := c.dissociateReference(repoDir); err != nil {
+			return "", err
+		}
+	}
+
+	return repoDir, nil
+}
+
+// pullLfsObjects downloads Git LFS objects of the checked out revision.
+func (c *GitClone) pullLfsObjects(repoDir string) error {
+	if err := c.CliWrappers.GitCli.LfsInstall(repoDir); err != nil {
+		return err
+	}
+	lfsPullArgs := &cliWrappers.GitLfsPullArgs{
+		RepoDir: repoDir,
+		Include: c.Params.LfsInclude,
+		Exclude: c.Params.LfsExclude,
+	}
+	return c.CliWrappers.GitCli.LfsPull(lfsPullArgs)
+}
+
+// getRepoDirName returns the directory name git uses for cloning the given repository url.
+func getRepoDirName(url string) string {
+	url = strings.TrimRight(url, "/")
+	name := url[strings.LastIndexAny(url, "/:")+1:]
+	return strings.TrimSuffix(name, ".git")
+}
+
+// validateRepoUrl checks that the repository url uses https or ssh protocol and cannot be taken as a command option.
+func validateRepoUrl(repoUrl string) error {
+	if isOptionLike(repoUrl) {
+		return errors.New("repository url must not start with '-' or contain whitespace")
+	}
+	if !strings.HasPrefix(repoUrl, "https://") && !isSshUrl(repoUrl) {
+		return errors.New("only https and ssh protocols are supported")
+	}
+	// ssh takes host starting with dash as an option, e.g. ssh://-oProxyCommand=...
+	if parsedUrl, err := url.Parse(repoUrl); err == nil && strings.HasPrefix(parsedUrl.Hostname(), "-") {
+		return errors.New("repository url host is invalid")
+	}
+	return nil
+}
+
 func (c *GitClone) validateParams() error {
 	if c.Params.RepoUrl == "" {
 		return errors.New("git repository url must be set")
 	}
-	if !strings.HasPrefix(c.Params.RepoUrl, "https://") {
-		return errors.New("only https protocol is supported")
+	if err := validateRepoUrl(c.Params.RepoUrl); err != nil {
+		return err
+	}
+	if err := checkUrlPolicy(c.Params.RepoUrl, c.urlPolicy); err != nil {
+		return err
+	}
+	if c.Params.DeleteExisting && c.Params.UpdateExisting {
+		return errors.New("delete-existing and update-existing parameters are mutually exclusive")
+	}
+	if strings.HasPrefix(c.Params.OutputDir, "-") {
+		return fmt.Errorf("output dir '%s' is invalid", c.Params.OutputDir)
+	}
+	if c.Params.MergeSha != "" && !cliWrappers.FullShaRegex.MatchString(c.Params.MergeSha) {
+		return fmt.Errorf("merge sha '%s' is not a full commit SHA", c.Params.MergeSha)
+	}
+	if strings.HasPrefix(c.Params.MergeTargetBranch, "-") {
+		return fmt.Errorf("merge target branch '%s' is invalid", c.Params.MergeTargetBranch)
+	}
+	if c.Params.Filter != "" && !partialCloneFilterRegex.MatchString(c.Params.Filter) {
+		return fmt.Errorf("filter '%s' is invalid", c.Params.Filter)
+	}
+	for _, sparsePath := range c.Params.SparsePaths {
+		if strings.HasPrefix(sparsePath, "-") {
+			return fmt.Errorf("sparse path '%s' is invalid", sparsePath)
+		}
+	}
+	switch c.Params.Submodules {
+	case "", submodulesModeOff, submodulesModeTopLevel, submodulesModeRecursive:
+	default:
+		return fmt.Errorf("submodules mode '%s' is invalid, supported values: %s, %s, %s",
+			c.Params.Submodules, submodulesModeOff, submodulesModeTopLevel, submodulesModeRecursive)
+	}
+	if c.Params.VerifySignature && c.Params.GpgKeyring == "" && c.Params.SshAllowedSigners == "" {
+		return errors.New("verify-signature requires gpg-keyring or ssh-allowed-signers to be set")
+	}
+	if strings.HasPrefix(c.Params.BaseRef, "-") || strings.ContainsAny(c.Params.BaseRef, " \t\n:") {
+		return fmt.Errorf("base ref '%s' is invalid", c.Params.BaseRef)
+	}
+	if len(c.Params.WatchPaths) > 0 && c.Params.BaseRef == "" {
+		return errors.New("watch-paths parameter requires base-ref to be set")
+	}
+	if c.Params.Manifest != "" && c.Params.ManifestParallelism < 1 {
+		return errors.New("manifest-parallelism must be positive")
+	}
+	if c.Params.Chown != "" {
+		if _, _, err := parseChownSpec(c.Params.Chown); err != nil {
+			return err
+		}
+	}
+	if c.Params.ChmodMode != "" {
+		if _, err := parseChmodMode(c.Params.ChmodMode); err != nil {
+			return err
+		}
+	}
+	switch c.Params.ArchiveFormat {
+	case "":
+		if c.Params.ArchivePath != "" {
+			return errors.New("archive-path parameter requires archive-format to be set")
+		}
+	case archiveFormatTarGz, archiveFormatGitBundle:
+		if c.Params.ArchivePath == "" {
+			return errors.New("archive-format parameter requires archive-path to be set")
+		}
+		if strings.HasPrefix(c.Params.ArchivePath, "-") {
+			return fmt.Errorf("archive path '%s' is invalid", c.Params.ArchivePath)
+		}
+	default:
+		return fmt.Errorf("archive format '%s' is invalid, supported values: %s, %s",
+			c.Params.ArchiveFormat, archiveFormatTarGz, archiveFormatGitBundle)
+	}
+	if c.Params.Revision != "" {
+		if strings.HasPrefix(c.Params.Revision, "-") || strings.ContainsAny(c.Params.Revision, " \t\n") {
+			return fmt.Errorf("revision '%s' is invalid", c.Params.Revision)
+		}
+	}
+	if err := validateNetworkConfig(c.Params.networkConfig()); err != nil {
+		return err
 	}
 
 	return nil
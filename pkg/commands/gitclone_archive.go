@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	archiveFormatTarGz     = "tar.gz"
+	archiveFormatGitBundle = "git-bundle"
+)
+
+// createSourceArchive writes the checked out sources into the archive of the requested format.
+// Returns sha256 digest of the archive in hex form.
+func (c *GitClone) createSourceArchive(repoDir string) (string, error) {
+	archivePath, err := filepath.Abs(c.Params.ArchivePath)
+	if err != nil {
+		return "", err
+	}
+	absRepoDir, err := filepath.Abs(repoDir)
+	if err != nil {
+		return "", err
+	}
+	if archivePath == absRepoDir || strings.HasPrefix(archivePath, absRepoDir+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive path '%s' must be outside of the source directory", c.Params.ArchivePath)
+	}
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	switch c.Params.ArchiveFormat {
+	case archiveFormatTarGz:
+		commitInfo, err := c.CliWrappers.GitCli.GetCommitInfo(repoDir, "HEAD")
+		if err != nil {
+			return "", fmt.Errorf("failed to get commit date: %w", err)
+		}
+		if err := createTarGzArchive(repoDir, archivePath, commitInfo.CommitterDate); err != nil {
+			return "", err
+		}
+	case archiveFormatGitBundle:
+		if err := c.CliWrappers.GitCli.CreateBundle(repoDir, archivePath); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("archive format '%s' is not supported", c.Params.ArchiveFormat)
+	}
+
+	return getFileSha256(archivePath)
+}
+
+// createTarGzArchive writes the content of the source directory, except .git, into a reproducible tar.gz archive.
+// Entries are sorted by path, have modification time set to the given one, and no owner information.
+func createTarGzArchive(sourceDir, archivePath string, modTime time.Time) error {
+	archiveFile, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer archiveFile.Close()
+
+	// Gzip header has no name and modification time set by default.
+	gzipWriter := gzip.NewWriter(archiveFile)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	// WalkDir visits entries in lexical order, which makes the archive content order stable.
+	err = filepath.WalkDir(sourceDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == sourceDir {
+			return nil
+		}
+		// Skip git metadata, including .git files of submodules.
+		if entry.Name() == ".git" {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		relPath, err := filepath.Rel(sourceDir, path)
+		if err != nil {
+			return err
+		}
+		return addTarEntry(tarWriter, path, filepath.ToSlash(relPath), entry, modTime)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return archiveFile.Close()
+}
+
+// addTarEntry writes a normalized header and the content of the given file into the tar archive.
+func addTarEntry(tarWriter *tar.Writer, path, name string, entry fs.DirEntry, modTime time.Time) error {
+	info, err := entry.Info()
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{
+		Name:    name,
+		ModTime: modTime,
+		Format:  tar.FormatPAX,
+	}
+	// Keep only the executable bit, like git does.
+	switch {
+	case entry.IsDir():
+		header.Typeflag = tar.TypeDir
+		header.Name += "/"
+		header.Mode = 0755
+	case info.Mode()&fs.ModeSymlink != 0:
+		header.Typeflag = tar.TypeSymlink
+		header.Mode = 0777
+		if header.Linkname, err = os.Readlink(path); err != nil {
+			return err
+		}
+	case info.Mode().IsRegular():
+		header.Typeflag = tar.TypeReg
+		header.Mode = 0644
+		if info.Mode()&0111 != 0 {
+			header.Mode = 0755
+		}
+		header.Size = info.Size()
+	default:
+		return fmt.Errorf("unsupported file type of '%s'", name)
+	}
+
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	if header.Typeflag != tar.TypeReg {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(tarWriter, file)
+	return err
+}
+
+// getFileSha256 returns sha256 digest of the file content in hex form.
+func getFileSha256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	cliWrappers "github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+const (
+	// baseRefLocalRef is the local ref the base ref is fetched into.
+	baseRefLocalRef = "refs/konflux/base"
+	// baseRefDeepenStep is the number of commits fetched on each attempt to find the base in shallow history.
+	baseRefDeepenStep = 50
+	// baseRefMaxDeepenAttempts limits the number of attempts before fetching the whole history.
+	baseRefMaxDeepenAttempts = 5
+)
+
+// getChangedFiles returns paths of files changed in the checked out commit since it diverged from the base ref.
+func (c *GitClone) getChangedFiles(repoDir string) ([]string, error) {
+	fetchArgs := &cliWrappers.GitFetchArgs{
+		RepoDir:  repoDir,
+		Remote:   "origin",
+		Refspecs: []string{"+" + c.Params.BaseRef + ":" + baseRefLocalRef},
+		Depth:    c.Params.Depth,
+		Filter:   c.Params.Filter,
+	}
+	if err := c.CliWrappers.GitCli.Fetch(fetchArgs); err != nil {
+		return nil, fmt.Errorf("failed to fetch base ref: %w", err)
+	}
+
+	mergeBase, err := c.findMergeBase(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	if mergeBase == "" {
+		return nil, errors.New("checked out commit and base ref have no common history")
+	}
+	l.Logger.Infof("Comparing with base ref commit %s", mergeBase)
+
+	return c.CliWrappers.GitCli.DiffNames(repoDir, mergeBase, "HEAD")
+}
+
+// findMergeBase returns the common ancestor of HEAD and the fetched base ref.
+// The history of shallow repository is deepened until the common ancestor is found.
+// Returns empty string if there is no common ancestor in the whole history.
+func (c *GitClone) findMergeBase(repoDir string) (string, error) {
+	for attempt := 1; ; attempt++ {
+		mergeBase, err := c.CliWrappers.GitCli.MergeBase(repoDir, "HEAD", baseRefLocalRef)
+		if err != nil || mergeBase != "" {
+			return mergeBase, err
+		}
+
+		isShallow, err := c.CliWrappers.GitCli.IsShallowRepository(repoDir)
+		if err != nil {
+			return "", err
+		}
+		if !isShallow {
+			return "", nil
+		}
+
+		fetchArgs := &cliWrappers.GitFetchArgs{
+			RepoDir:  repoDir,
+			Remote:   "origin",
+			Refspecs: []string{"+" + c.Params.BaseRef + ":" + baseRefLocalRef},
+			Filter:   c.Params.Filter,
+		}
+		if attempt <= baseRefMaxDeepenAttempts {
+			l.Logger.Infof("Base ref is not found in shallow history, deepening by %d commits", baseRefDeepenStep)
+			fetchArgs.Deepen = baseRefDeepenStep
+		} else {
+			l.Logger.Info("Base ref is not found in shallow history, fetching the whole history")
+			fetchArgs.Unshallow = true
+		}
+		if err := c.CliWrappers.GitCli.Fetch(fetchArgs); err != nil {
+			return "", fmt.Errorf("failed to deepen history: %w", err)
+		}
+	}
+}
+
+// watchPathToRegex converts glob pattern of a watched path into regular expression.
+// "*" and "?" don't match "/", while "**" matches any number of directories.
+// A pattern matching a directory matches all files in it.
+func watchPathToRegex(pattern string) *regexp.Regexp {
+	pattern = strings.TrimPrefix(pattern, "./")
+	pattern = strings.Trim(pattern, "/")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			sb.WriteString("[^/]*")
+		case pattern[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	sb.WriteString("(/.*)?$")
+	return regexp.MustCompile(sb.String())
+}
+
+// isAnyWatchedPathChanged checks if any of the changed files matches any of the watched path patterns.
+func isAnyWatchedPathChanged(changedFiles, watchPaths []string) bool {
+	for _, watchPath := range watchPaths {
+		watchPathRegex := watchPathToRegex(watchPath)
+		for _, changedFile := range changedFiles {
+			if watchPathRegex.MatchString(changedFile) {
+				return true
+			}
+		}
+	}
+	return false
+}
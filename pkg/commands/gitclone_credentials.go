@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+	"github.com/mmorhun/konflux-task-cli/pkg/redact"
+)
+
+const (
+	basicAuthGitCredentialsFileName = ".git-credentials"
+	basicAuthGitConfigFileName      = ".gitconfig"
+)
+
+// isHttpsAuthConfigured checks if any https credentials are provided.
+// The token is meant for the primary repository only, so it's ignored if the repository uses ssh.
+func (c *GitClone) isHttpsAuthConfigured() bool {
+	return c.Params.BasicAuthDirectory != "" || c.isTokenUsed()
+}
+
+func (c *GitClone) isTokenUsed() bool {
+	return c.Params.TokenFile != "" && !isSshUrl(c.Params.RepoUrl)
+}
+
+// setupHttpsAuth configures git to use credentials from the basic auth directory and the token file.
+// The token is used for the primary repository host only, other hosts need entries in the basic auth credentials.
+// The credentials are saved into a temporary credential store, which is used only by git commands of this clone.
+// Returns a function that removes the temporary credential store.
+func (c *GitClone) setupHttpsAuth() (func(), error) {
+	credentialsDir, err := os.MkdirTemp("", "gitclone-credentials-*")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(credentialsDir); err != nil {
+			l.Logger.Warnf("failed to remove temporary credentials directory '%s': %s", credentialsDir, err.Error())
+		}
+	}
+
+	var credentials []string
+	if c.Params.BasicAuthDirectory != "" {
+		gitCredentialsPath := filepath.Join(c.Params.BasicAuthDirectory, basicAuthGitCredentialsFileName)
+		gitCredentials, err := os.ReadFile(gitCredentialsPath)
+		if err != nil && !os.IsNotExist(err) {
+			cleanup()
+			return nil, fmt.Errorf("failed to read %s: %w", basicAuthGitCredentialsFileName, err)
+		}
+		if len(gitCredentials) > 0 {
+			registerGitCredentialsSecrets(string(gitCredentials))
+			credentials = append(credentials, strings.TrimSpace(string(gitCredentials)))
+		}
+
+		gitConfigPath := filepath.Join(c.Params.BasicAuthDirectory, basicAuthGitConfigFileName)
+		if _, err := os.Stat(gitConfigPath); err == nil {
+			c.CliWrappers.GitCli.AddConfig("include.path", gitConfigPath)
+		}
+	}
+
+	if c.isTokenUsed() {
+		tokenCredentials, err := c.getTokenCredentials()
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		credentials = append(credentials, tokenCredentials)
+	}
+
+	if len(credentials) == 0 {
+		cleanup()
+		return nil, errors.New("no credentials found")
+	}
+
+	credentialStorePath := filepath.Join(credentialsDir, basicAuthGitCredentialsFileName)
+	if err := os.WriteFile(credentialStorePath, []byte(strings.Join(credentials, "\n")+"\n"), 0600); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	// Empty value resets the list of credential helpers, including the ones from the included basic auth .gitconfig,
+	// otherwise git would also save the credentials using those helpers, e.g. into $HOME/.git-credentials.
+	c.CliWrappers.GitCli.AddConfig("credential.helper", "")
+	c.CliWrappers.GitCli.AddConfig("credential.helper", "store --file="+credentialStorePath)
+	// Fail instead of waiting for input if the credentials are not accepted.
+	c.CliWrappers.GitCli.SetEnv("GIT_TERMINAL_PROMPT", "0")
+
+	return cleanup, nil
+}
+
+// getTokenCredentials reads token from the token file and returns credential store entry for the repository host.
+func (c *GitClone) getTokenCredentials() (string, error) {
+	tokenData, err := os.ReadFile(c.Params.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(tokenData))
+	if token == "" {
+		return "", errors.New("token file is empty")
+	}
+	if err := redact.AddSecret(token); err != nil {
+		l.Logger.Warnf("Token is not masked in logs: %v", err)
+	}
+
+	repoUrl, err := url.Parse(c.Params.RepoUrl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse repository url: %w", err)
+	}
+	username := c.Params.TokenUsername
+	if username == "" {
+		username = "git"
+	}
+	credentialsUrl := url.URL{
+		Scheme: repoUrl.Scheme,
+		User:   url.UserPassword(username, token),
+		Host:   repoUrl.Host,
+	}
+	return credentialsUrl.String(), nil
+}
+
+// registerGitCredentialsSecrets registers passwords from git credential store entries as secrets,
+// so they are masked even if they appear outside of an url.
+func registerGitCredentialsSecrets(gitCredentials string) {
+	for _, line := range strings.Split(gitCredentials, "\n") {
+		credentialsUrl, err := url.Parse(strings.TrimSpace(line))
+		if err != nil || credentialsUrl.User == nil {
+			continue
+		}
+		if password, hasPassword := credentialsUrl.User.Password(); hasPassword {
+			if err := redact.AddSecret(password); err != nil {
+				l.Logger.Warnf("Password for '%s' from git credentials is not masked in logs: %v", credentialsUrl.Host, err)
+			}
+		}
+	}
+}
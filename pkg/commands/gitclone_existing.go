@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cliWrappers "github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+	"github.com/mmorhun/konflux-task-cli/pkg/common"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+// updateExistingClone updates a clone of the same repository in the given directory to the requested revision.
+// The directory content is deleted if it contains anything else or the update fails.
+// Returns true if the existing clone was updated, false if a fresh clone is needed.
+func (c *GitClone) updateExistingClone(repoDir string) bool {
+	if _, err := os.Stat(filepath.Join(repoDir, ".git")); err != nil {
+		if os.IsNotExist(err) {
+			l.Logger.Infof("No existing clone found in '%s'", repoDir)
+		} else {
+			l.Logger.Warnf("Failed to check existing clone in '%s': %s", repoDir, err.Error())
+		}
+		// Leftovers, e.g. of an interrupted clone, would break the clone or end up in the sources.
+		if err := common.RemoveDirContent(repoDir); err != nil {
+			l.Logger.Warnf("Failed to delete content of '%s': %s", repoDir, err.Error())
+		}
+		return false
+	}
+
+	if err := c.fetchIntoExistingClone(repoDir); err != nil {
+		l.Logger.Warnf("Failed to update existing clone in '%s', cloning from scratch: %s", repoDir, err.Error())
+		if err := common.RemoveDirContent(repoDir); err != nil {
+			l.Logger.Warnf("Failed to delete existing clone in '%s': %s", repoDir, err.Error())
+		}
+		return false
+	}
+
+	l.Logger.Infof("Updated existing clone in '%s'", repoDir)
+	return true
+}
+
+func (c *GitClone) fetchIntoExistingClone(repoDir string) error {
+	remoteUrl, err := c.CliWrappers.GitCli.GetRemoteUrl(repoDir, "origin")
+	if err != nil {
+		return err
+	}
+	if normalizeRepoUrl(remoteUrl) != normalizeRepoUrl(c.Params.RepoUrl) {
+		return fmt.Errorf("existing clone has different remote '%s'", remoteUrl)
+	}
+
+	ref := c.Params.Revision
+	if ref == "" {
+		ref = c.Params.Branch
+	}
+	fetchArgs := &cliWrappers.GitFetchArgs{
+		RepoDir:  repoDir,
+		Remote:   "origin",
+		Refspecs: []string{ref},
+		Depth:    c.Params.Depth,
+		Filter:   c.Params.Filter,
+	}
+	if err := c.CliWrappers.GitCli.Fetch(fetchArgs); err != nil {
+		return err
+	}
+	if len(c.Params.SparsePaths) > 0 {
+		if err := c.CliWrappers.GitCli.SparseCheckoutSet(repoDir, c.Params.SparsePaths); err != nil {
+			return err
+		}
+	}
+	if err := c.CliWrappers.GitCli.ResetHard(repoDir, "FETCH_HEAD"); err != nil {
+		return err
+	}
+	return c.CliWrappers.GitCli.Clean(repoDir)
+}
+
+// normalizeRepoUrl strips parts of git url that don't change the repository it points to.
+func normalizeRepoUrl(url string) string {
+	url = strings.TrimRight(url, "/")
+	return strings.TrimSuffix(url, ".git")
+}
@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	cliWrappers "github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+// GitManifestEntry describes an additional repository to clone.
+type GitManifestEntry struct {
+	Url string `yaml:"url" json:"url"`
+	// Revision is a branch, tag, ref or commit to checkout. The remote HEAD if not set.
+	Revision string `yaml:"revision" json:"revision,omitempty"`
+	// Path to clone into, derived from the url if not set.
+	Path  string `yaml:"path" json:"path"`
+	Depth int    `yaml:"depth" json:"depth,omitempty"`
+}
+
+// GitManifestResult holds the resolved state of a cloned manifest entry.
+type GitManifestResult struct {
+	Url      string `json:"url"`
+	Revision string `json:"revision,omitempty"`
+	Path     string `json:"path"`
+	Commit   string `json:"commit"`
+}
+
+// loadManifest reads the list of additional repositories from the given YAML or JSON file.
+// Entry paths are defaulted and validated, so each repository is cloned into its own directory.
+func loadManifest(manifestPath string) ([]GitManifestEntry, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	// JSON is a subset of YAML, so both formats are parsed by YAML decoder.
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var entries []GitManifestEntry
+	if err := decoder.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("manifest has no entries")
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Url == "" {
+			return nil, fmt.Errorf("manifest entry %d has no url", i)
+		}
+		if entry.Path == "" {
+			entry.Path = getRepoDirName(entry.Url)
+		}
+		entry.Path = filepath.Clean(entry.Path)
+		if filepath.IsAbs(entry.Path) || entry.Path == "." || entry.Path == ".." ||
+			strings.HasPrefix(entry.Path, "../") || strings.HasPrefix(entry.Path, "-") {
+			return nil, fmt.Errorf("manifest entry %d path '%s' is invalid, it must be relative to the working directory", i, entry.Path)
+		}
+		if strings.HasPrefix(entry.Revision, "-") || strings.ContainsAny(entry.Revision, " \t\n") {
+			return nil, fmt.Errorf("manifest entry %d revision '%s' is invalid", i, entry.Revision)
+		}
+		if entry.Depth < 0 {
+			return nil, fmt.Errorf("manifest entry %d depth must not be negative", i)
+		}
+	}
+	return entries, nil
+}
+
+// checkManifestPaths verifies that the repositories don't overlap with each other and the primary source dir.
+func checkManifestPaths(entries []GitManifestEntry, sourceDir string) error {
+	paths := []string{filepath.Clean(sourceDir)}
+	for _, entry := range entries {
+		for _, path := range paths {
+			if entry.Path == path || strings.HasPrefix(entry.Path, path+"/") || strings.HasPrefix(path, entry.Path+"/") {
+				return fmt.Errorf("manifest entry path '%s' overlaps with '%s'", entry.Path, path)
+			}
+		}
+		paths = append(paths, entry.Path)
+	}
+	return nil
+}
+
+// cloneManifest clones all manifest repositories, up to the manifest parallelism at once.
+// Returns the results in the manifest order.
+func (c *GitClone) cloneManifest(entries []GitManifestEntry) ([]GitManifestResult, error) {
+	results := make([]GitManifestResult, len(entries))
+	errs := make([]error, len(entries))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, c.Params.ManifestParallelism)
+	for i, entry := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			l.Logger.Infof("Cloning '%s' into '%s'", entry.Url, entry.Path)
+			commit, err := c.cloneManifestEntry(entry)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to clone '%s' into '%s': %w", entry.Url, entry.Path, err)
+				return
+			}
+			results[i] = GitManifestResult{
+				Url:      entry.Url,
+				Revision: entry.Revision,
+				Path:     entry.Path,
+				Commit:   commit,
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// cloneManifestEntry checks out the requested revision of the manifest repository.
+// Returns the checked out commit.
+func (c *GitClone) cloneManifestEntry(entry GitManifestEntry) (string, error) {
+	if entry.Revision == "" {
+		cloneArgs := &cliWrappers.GitCloneArgs{
+			Url:       entry.Url,
+			Depth:     entry.Depth,
+			Directory: entry.Path,
+		}
+		if _, err := c.CliWrappers.GitCli.Clone(cloneArgs); err != nil {
+			return "", err
+		}
+	} else {
+		if err := c.CliWrappers.GitCli.Init(entry.Path); err != nil {
+			return "", err
+		}
+		if err := c.CliWrappers.GitCli.AddRemote(entry.Path, "origin", entry.Url); err != nil {
+			return "", err
+		}
+		fetchArgs := &cliWrappers.GitFetchArgs{
+			RepoDir:  entry.Path,
+			Remote:   "origin",
+			Refspecs: []string{entry.Revision},
+			Depth:    entry.Depth,
+		}
+		if err := c.CliWrappers.GitCli.Fetch(fetchArgs); err != nil {
+			return "", err
+		}
+		if err := c.CliWrappers.GitCli.Checkout(entry.Path, "FETCH_HEAD"); err != nil {
+			return "", err
+		}
+	}
+	return c.CliWrappers.GitCli.GetRepoHeadFullSha(entry.Path)
+}
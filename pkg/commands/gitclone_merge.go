@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"fmt"
+
+	cliWrappers "github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+// Identity used for merge commits of pre-merge builds.
+const (
+	mergeCommitUserName  = "Konflux CI"
+	mergeCommitUserEmail = "noreply@konflux-ci.dev"
+)
+
+func (c *GitClone) isMergeRequested() bool {
+	return c.Params.MergeTargetBranch != "" || c.Params.MergeSha != ""
+}
+
+// mergeTargetBranch merges the target branch into the checked out revision.
+// Returns the commit SHA of the checked out revision before the merge.
+func (c *GitClone) mergeTargetBranch(repoDir string) (string, error) {
+	originalCommitSha, err := c.CliWrappers.GitCli.GetRepoHeadFullSha(repoDir)
+	if err != nil {
+		return "", err
+	}
+
+	// Merge requires common history of both branches.
+	if c.Params.Depth > 0 {
+		l.Logger.Info("Fetching full history to find merge base")
+		unshallowArgs := &cliWrappers.GitFetchArgs{
+			RepoDir:   repoDir,
+			Remote:    "origin",
+			Unshallow: true,
+			Filter:    c.Params.Filter,
+		}
+		if err := c.CliWrappers.GitCli.Fetch(unshallowArgs); err != nil {
+			return "", err
+		}
+	}
+
+	mergeRef := c.Params.MergeSha
+	if mergeRef == "" {
+		mergeRef = c.Params.MergeTargetBranch
+	}
+	fetchArgs := &cliWrappers.GitFetchArgs{
+		RepoDir:  repoDir,
+		Remote:   "origin",
+		Refspecs: []string{mergeRef},
+		Filter:   c.Params.Filter,
+	}
+	if err := c.CliWrappers.GitCli.Fetch(fetchArgs); err != nil {
+		return "", err
+	}
+
+	mergeTarget := c.Params.MergeTargetBranch
+	if mergeTarget == "" {
+		mergeTarget = c.Params.MergeSha
+	}
+	mergeArgs := &cliWrappers.GitMergeArgs{
+		RepoDir:   repoDir,
+		Ref:       "FETCH_HEAD",
+		Message:   fmt.Sprintf("Merge %s into %s", mergeTarget, originalCommitSha),
+		UserName:  mergeCommitUserName,
+		UserEmail: mergeCommitUserEmail,
+	}
+	if err := c.CliWrappers.GitCli.Merge(mergeArgs); err != nil {
+		return "", err
+	}
+
+	return originalCommitSha, nil
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mmorhun/konflux-task-cli/pkg/common"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+// writeCommitMetadataResults writes requested optional results with HEAD commit metadata.
+func (c *GitClone) writeCommitMetadataResults(repoDir string) error {
+	if c.Results.CommitAuthor != "" || c.Results.CommitTimestamp != "" || c.Results.CommitDate != "" || c.Results.CommitMessage != "" {
+		commitInfo, err := c.CliWrappers.GitCli.GetCommitInfo(repoDir, "HEAD")
+		if err != nil {
+			return fmt.Errorf("failed to get commit info: %w", err)
+		}
+		commitTimestamp := strconv.FormatInt(commitInfo.CommitterDate.Unix(), 10)
+		commitDate := commitInfo.CommitterDate.Format(time.RFC3339)
+
+		if err := common.WriteOptionalResultString(c.ResultsWriter, commitInfo.Author, c.Results.CommitAuthor); err != nil {
+			return err
+		}
+		if err := common.WriteOptionalResultString(c.ResultsWriter, commitTimestamp, c.Results.CommitTimestamp); err != nil {
+			return err
+		}
+		if err := common.WriteOptionalResultString(c.ResultsWriter, commitDate, c.Results.CommitDate); err != nil {
+			return err
+		}
+		if err := common.WriteOptionalResultString(c.ResultsWriter, commitInfo.Subject, c.Results.CommitMessage); err != nil {
+			return err
+		}
+
+		if c.Params.Verbose {
+			l.Logger.Infof("[result] commit author: %s", commitInfo.Author)
+			l.Logger.Infof("[result] commit timestamp: %s", commitTimestamp)
+			l.Logger.Infof("[result] commit date: %s", commitDate)
+			l.Logger.Infof("[result] commit message: %s", commitInfo.Subject)
+		}
+	}
+
+	if c.Results.CommitDescribe != "" {
+		describe, err := c.CliWrappers.GitCli.Describe(repoDir)
+		if err != nil {
+			return fmt.Errorf("failed to describe commit: %w", err)
+		}
+		if err := c.ResultsWriter.WriteResultString(describe, c.Results.CommitDescribe); err != nil {
+			return err
+		}
+
+		if c.Params.Verbose {
+			l.Logger.Infof("[result] commit describe: %s", describe)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// parseChownSpec parses numeric "uid[:gid]" owner specification.
+// Returns -1 gid if it's not set, which keeps the group unchanged.
+func parseChownSpec(spec string) (int, int, error) {
+	uidStr, gidStr, hasGid := strings.Cut(spec, ":")
+	uid, err := strconv.Atoi(uidStr)
+	if err != nil || uid < 0 {
+		return 0, 0, fmt.Errorf("chown '%s' is invalid, expected numeric uid[:gid]", spec)
+	}
+	gid := -1
+	if hasGid {
+		gid, err = strconv.Atoi(gidStr)
+		if err != nil || gid < 0 {
+			return 0, 0, fmt.Errorf("chown '%s' is invalid, expected numeric uid[:gid]", spec)
+		}
+	}
+	return uid, gid, nil
+}
+
+// parseChmodMode parses octal permissions, e.g. "0775".
+// The owner must keep full access to directories, otherwise the sources cannot be traversed.
+func parseChmodMode(mode string) (fs.FileMode, error) {
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || perm > 0777 {
+		return 0, fmt.Errorf("chmod mode '%s' is invalid, expected octal permissions, e.g. 0775", mode)
+	}
+	if perm&0700 != 0700 {
+		return 0, fmt.Errorf("chmod mode '%s' is invalid, owner must have read, write and execute permissions", mode)
+	}
+	return fs.FileMode(perm), nil
+}
+
+// fixSourcesPermissions applies requested permissions and ownership to all files of the repository,
+// including git metadata, so the sources can be used by steps running as a different user.
+// Execute bits of the mode are set only on directories and files which were executable before.
+func (c *GitClone) fixSourcesPermissions(repoDir string) error {
+	var mode fs.FileMode
+	var err error
+	if c.Params.ChmodMode != "" {
+		if mode, err = parseChmodMode(c.Params.ChmodMode); err != nil {
+			return err
+		}
+	}
+	uid, gid := -1, -1
+	if c.Params.Chown != "" {
+		if uid, gid, err = parseChownSpec(c.Params.Chown); err != nil {
+			return err
+		}
+	}
+
+	return filepath.WalkDir(repoDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if c.Params.ChmodMode != "" && entry.Type()&fs.ModeSymlink == 0 {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			newMode := mode
+			if !entry.IsDir() && info.Mode().Perm()&0111 == 0 {
+				newMode &^= 0111
+			}
+			if err := os.Chmod(path, newMode); err != nil {
+				return err
+			}
+		}
+		if c.Params.Chown != "" {
+			if err := os.Lchown(path, uid, gid); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
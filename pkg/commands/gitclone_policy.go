@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/mmorhun/konflux-task-cli/pkg/redact"
+)
+
+// UrlPolicy restricts git repositories that are allowed to be cloned.
+// Each entry is a host name, optionally with "*." prefix to match subdomains,
+// followed by an optional path prefix, e.g. "github.com/konflux-ci" or "*.example.com".
+// Deny entries take precedence. If allow list is not empty, the url must match one of its entries.
+type UrlPolicy struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// UrlPolicyViolationError is returned when the repository url is not allowed.
+type UrlPolicyViolationError struct {
+	Url    string
+	Reason string
+}
+
+func (e *UrlPolicyViolationError) Error() string {
+	return fmt.Sprintf("repository url '%s' violates policy: %s", e.Url, e.Reason)
+}
+
+// normalizedGitUrl holds the parts of git url the policy is applied to.
+// Host is lowercased and has no port. Path is lowercased, has no leading or trailing slashes and no .git suffix.
+type normalizedGitUrl struct {
+	host string
+	path string
+}
+
+func (u *normalizedGitUrl) String() string {
+	return u.host + "/" + u.path
+}
+
+// loadUrlPolicy reads the url policy from the given JSON file.
+func loadUrlPolicy(policyFilePath string) (*UrlPolicy, error) {
+	data, err := os.ReadFile(policyFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read url policy file: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	policy := &UrlPolicy{}
+	if err := decoder.Decode(policy); err != nil {
+		return nil, fmt.Errorf("failed to parse url policy file: %w", err)
+	}
+	for _, entry := range append(policy.Allow, policy.Deny...) {
+		if strings.Trim(entry, "/") == "" {
+			return nil, fmt.Errorf("url policy file contains empty entry")
+		}
+	}
+	return policy, nil
+}
+
+// normalizeGitUrl parses https, ssh or scp-like git url.
+// Fails on urls with embedded credentials or relative path segments, which could be used to bypass the policy.
+func normalizeGitUrl(rawUrl string) (*normalizedGitUrl, error) {
+	if isOptionLike(rawUrl) {
+		return nil, &UrlPolicyViolationError{Url: rawUrl, Reason: "url must not start with '-' or contain whitespace"}
+	}
+
+	var host, path string
+	if strings.HasPrefix(rawUrl, "https://") || strings.HasPrefix(rawUrl, "ssh://") {
+		parsedUrl, err := url.Parse(rawUrl)
+		if err != nil {
+			return nil, &UrlPolicyViolationError{Url: rawUrl, Reason: "url is malformed"}
+		}
+		if parsedUrl.User != nil {
+			_, hasPassword := parsedUrl.User.Password()
+			// Any user info in https url is a credential, e.g. a token, while ssh needs a user name.
+			if hasPassword || parsedUrl.Scheme == "https" {
+				return nil, &UrlPolicyViolationError{Url: redact.String(rawUrl), Reason: "embedded credentials are forbidden, use credentials parameters instead"}
+			}
+		}
+		if parsedUrl.RawQuery != "" || parsedUrl.Fragment != "" {
+			return nil, &UrlPolicyViolationError{Url: rawUrl, Reason: "query and fragment are not allowed"}
+		}
+		host = parsedUrl.Hostname()
+		path = parsedUrl.Path
+	} else if isSshUrl(rawUrl) {
+		hostPart, pathPart, _ := strings.Cut(rawUrl, ":")
+		if i := strings.LastIndex(hostPart, "@"); i >= 0 {
+			hostPart = hostPart[i+1:]
+		}
+		host = hostPart
+		path = pathPart
+	} else {
+		return nil, &UrlPolicyViolationError{Url: rawUrl, Reason: "only https and ssh protocols are supported"}
+	}
+
+	// Fully qualified host name with trailing dot is the same host, e.g. "github.com."
+	host = strings.TrimSuffix(host, ".")
+	if host == "" {
+		return nil, &UrlPolicyViolationError{Url: rawUrl, Reason: "host is missing"}
+	}
+	// ssh takes host starting with dash as an option, e.g. ssh://-oProxyCommand=...
+	if isOptionLike(host) {
+		return nil, &UrlPolicyViolationError{Url: rawUrl, Reason: "host is invalid"}
+	}
+	path = strings.Trim(path, "/")
+	path = strings.TrimSuffix(path, ".git")
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return nil, &UrlPolicyViolationError{Url: rawUrl, Reason: "repository path is invalid"}
+		}
+	}
+
+	return &normalizedGitUrl{
+		host: strings.ToLower(host),
+		path: strings.ToLower(path),
+	}, nil
+}
+
+// matches checks if the url matches the given policy entry.
+// Path prefix of the entry must match whole path segments.
+func (u *normalizedGitUrl) matches(entry string) bool {
+	entry = strings.ToLower(strings.Trim(entry, "/"))
+	entry = strings.TrimSuffix(entry, ".git")
+	entryHost, entryPath, _ := strings.Cut(entry, "/")
+	entryHost = strings.TrimSuffix(entryHost, ".")
+
+	if strings.HasPrefix(entryHost, "*.") {
+		if !strings.HasSuffix(u.host, entryHost[1:]) {
+			return false
+		}
+	} else if u.host != entryHost {
+		return false
+	}
+
+	if entryPath == "" {
+		return true
+	}
+	return u.path == entryPath || strings.HasPrefix(u.path, entryPath+"/")
+}
+
+// checkUrlPolicy verifies that the given repository url is allowed by the policy, if any.
+// Urls are normalized and checked for embedded credentials only if the policy is set.
+func checkUrlPolicy(rawUrl string, policy *UrlPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	gitUrl, err := normalizeGitUrl(rawUrl)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range policy.Deny {
+		if gitUrl.matches(entry) {
+			return &UrlPolicyViolationError{Url: rawUrl, Reason: fmt.Sprintf("'%s' matches denied '%s'", gitUrl, entry)}
+		}
+	}
+	if len(policy.Allow) == 0 {
+		return nil
+	}
+	for _, entry := range policy.Allow {
+		if gitUrl.matches(entry) {
+			return nil
+		}
+	}
+	return &UrlPolicyViolationError{Url: rawUrl, Reason: fmt.Sprintf("'%s' doesn't match any allowed host or path", gitUrl)}
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+// getReferenceObjectsDir returns objects directory of the given bare or non-bare repository.
+// Returns empty string if the directory doesn't contain a git repository.
+func getReferenceObjectsDir(referenceDir string) string {
+	for _, objectsDir := range []string{
+		filepath.Join(referenceDir, "objects"),
+		filepath.Join(referenceDir, ".git", "objects"),
+	} {
+		if info, err := os.Stat(objectsDir); err == nil && info.IsDir() {
+			if absObjectsDir, err := filepath.Abs(objectsDir); err == nil {
+				return absObjectsDir
+			}
+		}
+	}
+	return ""
+}
+
+// addReferenceAlternates makes the repository borrow objects from the reference repository,
+// the same way as git clone --reference-if-able does. Unusable reference repository is skipped.
+func addReferenceAlternates(repoDir, referenceDir string) error {
+	objectsDir := getReferenceObjectsDir(referenceDir)
+	if objectsDir == "" {
+		l.Logger.Warnf("Reference directory '%s' is not a git repository, skipping", referenceDir)
+		return nil
+	}
+
+	alternatesPath := filepath.Join(repoDir, ".git", "objects", "info", "alternates")
+	if err := os.MkdirAll(filepath.Dir(alternatesPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(alternatesPath, []byte(objectsDir+"\n"), 0644)
+}
+
+// dissociateReference copies objects borrowed from the reference repository
+// and makes the repository independent of it, like git clone --dissociate does.
+func (c *GitClone) dissociateReference(repoDir string) error {
+	alternatesPath := filepath.Join(repoDir, ".git", "objects", "info", "alternates")
+	if _, err := os.Stat(alternatesPath); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err := c.CliWrappers.GitCli.Repack(repoDir); err != nil {
+		return err
+	}
+	return os.Remove(alternatesPath)
+}
@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cliWrappers "github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+// gitSignatureStatusDescriptions explains git signature statuses other than good one.
+var gitSignatureStatusDescriptions = map[string]string{
+	"B": "bad signature",
+	"U": "signed by a key that is not trusted",
+	"X": "signature has expired",
+	"Y": "signed by an expired key",
+	"R": "signed by a revoked key",
+	"E": "signature cannot be checked, the signing key is not trusted",
+	"N": "commit is not signed",
+}
+
+// setupSignatureVerification configures git to trust only the keys given by parameters.
+// GPG keys are imported into a temporary gpg home directory, so keys of the user are never trusted.
+// Returns a function that removes the temporary gpg home directory.
+func (c *GitClone) setupSignatureVerification() (func(), error) {
+	gpgHomeDir, err := os.MkdirTemp("", "gitclone-gnupg-*")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(gpgHomeDir); err != nil {
+			l.Logger.Warnf("failed to remove temporary gpg home directory '%s': %s", gpgHomeDir, err.Error())
+		}
+	}
+
+	if c.Params.GpgKeyring != "" {
+		// The keyring is the trust anchor, so all its keys have full validity.
+		// Otherwise gpg reports good signatures with unknown validity.
+		gpgConfPath := filepath.Join(gpgHomeDir, "gpg.conf")
+		if err := os.WriteFile(gpgConfPath, []byte("trust-model always\n"), 0600); err != nil {
+			cleanup()
+			return nil, err
+		}
+		if err := c.CliWrappers.GpgCli.ImportKeys(gpgHomeDir, c.Params.GpgKeyring); err != nil {
+			cleanup()
+			return nil, fmt.Errorf("failed to import gpg keyring: %w", err)
+		}
+	}
+	c.CliWrappers.GitCli.SetEnv("GNUPGHOME", gpgHomeDir)
+
+	if c.Params.SshAllowedSigners != "" {
+		c.CliWrappers.GitCli.AddConfig("gpg.ssh.allowedSignersFile", c.Params.SshAllowedSigners)
+	}
+
+	return cleanup, nil
+}
+
+// verifyCommitSignature checks that HEAD commit is signed by a trusted key.
+// Returns the signer identity.
+func (c *GitClone) verifyCommitSignature(repoDir string) (string, error) {
+	signature, err := c.CliWrappers.GitCli.GetCommitSignature(repoDir, "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	if signature.Status != cliWrappers.GitSignatureStatusGood {
+		description, isKnown := gitSignatureStatusDescriptions[signature.Status]
+		if !isKnown {
+			description = fmt.Sprintf("unknown signature status '%s'", signature.Status)
+		}
+		if signature.Fingerprint != "" {
+			description += ", key: " + signature.Fingerprint
+		}
+		return "", errors.New(description)
+	}
+
+	signer := signature.Signer
+	if signer == "" {
+		signer = signature.Fingerprint
+	}
+	l.Logger.Infof("Commit signature is verified, signed by: %s", signer)
+	return signer, nil
+}
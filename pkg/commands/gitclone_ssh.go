@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+const (
+	sshKnownHostsFileName = "known_hosts"
+	sshConfigFileName     = "config"
+)
+
+// scpLikeUrlRegex matches scp-like git urls, e.g. git@github.com:org/repo.git
+var scpLikeUrlRegex = regexp.MustCompile(`^([^@/:]+@)?[^@/:]+:.+$`)
+
+// isOptionLike checks if the value could be taken as a command option or split into several arguments,
+// e.g. "--upload-pack=touch pwned;:x" given as a git url.
+func isOptionLike(value string) bool {
+	return strings.HasPrefix(value, "-") || strings.ContainsAny(value, " \t\r\n")
+}
+
+// isSshUrl checks if given git url uses ssh transport.
+// Both ssh://[user@]host[:port]/path and scp-like [user@]host:path forms are recognized.
+func isSshUrl(url string) bool {
+	if isOptionLike(url) {
+		return false
+	}
+	if strings.HasPrefix(url, "ssh://") {
+		return true
+	}
+	if strings.Contains(url, "://") {
+		return false
+	}
+	return scpLikeUrlRegex.MatchString(url)
+}
+
+// setupSsh configures ssh command for git according to ssh parameters.
+// The content of the ssh directory is copied into a temporary directory,
+// because ssh refuses to use private keys with too open permissions,
+// which is the case for mounted Kubernetes secrets.
+// Returns a function that removes the temporary ssh data.
+func (c *GitClone) setupSsh() (func(), error) {
+	sshDir, err := os.MkdirTemp("", "gitclone-ssh-*")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(sshDir); err != nil {
+			l.Logger.Warnf("failed to remove temporary ssh directory '%s': %s", sshDir, err.Error())
+		}
+	}
+
+	var identityFiles []string
+	var knownHostsFile, configFile string
+	if c.Params.SshDirectory != "" {
+		entries, err := os.ReadDir(c.Params.SshDirectory)
+		if err != nil {
+			cleanup()
+			return nil, fmt.Errorf("failed to read ssh directory: %w", err)
+		}
+		for _, entry := range entries {
+			fileName := entry.Name()
+			// Skip hidden files, including '..data' symlinks of mounted Kubernetes secrets.
+			if strings.HasPrefix(fileName, ".") {
+				continue
+			}
+			srcPath := filepath.Join(c.Params.SshDirectory, fileName)
+			// Stat follows symlinks, secret volume files are symlinks.
+			info, err := os.Stat(srcPath)
+			if err != nil {
+				cleanup()
+				return nil, err
+			}
+			if info.IsDir() {
+				continue
+			}
+
+			dstPath := filepath.Join(sshDir, fileName)
+			if err := copyFileWithMode(srcPath, dstPath, 0600); err != nil {
+				cleanup()
+				return nil, err
+			}
+
+			switch {
+			case fileName == sshKnownHostsFileName:
+				knownHostsFile = dstPath
+			case fileName == sshConfigFileName:
+				configFile = dstPath
+			case strings.HasSuffix(fileName, ".pub") || fileName == "authorized_keys":
+				// Not a private key
+			default:
+				identityFiles = append(identityFiles, dstPath)
+			}
+		}
+	}
+
+	sshCommand := []string{"ssh"}
+	if configFile != "" {
+		sshCommand = append(sshCommand, "-F", configFile)
+	}
+	for _, identityFile := range identityFiles {
+		sshCommand = append(sshCommand, "-i", identityFile)
+	}
+	if len(identityFiles) > 0 {
+		sshCommand = append(sshCommand, "-o", "IdentitiesOnly=yes")
+	}
+	if c.Params.SshSkipHostKeyVerification {
+		l.Logger.Warn("SSH host key verification is disabled")
+		sshCommand = append(sshCommand, "-o", "StrictHostKeyChecking=no", "-o", "UserKnownHostsFile=/dev/null")
+	} else {
+		sshCommand = append(sshCommand, "-o", "StrictHostKeyChecking=yes")
+		if knownHostsFile != "" {
+			sshCommand = append(sshCommand, "-o", "UserKnownHostsFile="+knownHostsFile)
+		} else {
+			l.Logger.Warn("known_hosts file is not provided, relying on the default one")
+		}
+	}
+
+	c.CliWrappers.GitCli.SetEnv("GIT_SSH_COMMAND", strings.Join(sshCommand, " "))
+
+	return cleanup, nil
+}
+
+// copyFileWithMode copies file content and sets the given permissions to the destination file.
+func copyFileWithMode(srcPath, dstPath string, mode os.FileMode) error {
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(dstPath, data, mode); err != nil {
+		return err
+	}
+	// Apply mode explicitly, WriteFile mode is affected by umask.
+	return os.Chmod(dstPath, mode)
+}
@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	cliWrappers "github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+)
+
+const (
+	submodulesModeOff       = "off"
+	submodulesModeTopLevel  = "top-level"
+	submodulesModeRecursive = "recursive"
+)
+
+// updateSubmodules checks out submodules according to the submodules parameters.
+// Returns the list of checked out submodules, empty if submodules are disabled.
+func (c *GitClone) updateSubmodules(repoDir string) ([]cliWrappers.GitSubmodule, error) {
+	if c.Params.Submodules == submodulesModeOff || c.Params.Submodules == "" {
+		return []cliWrappers.GitSubmodule{}, nil
+	}
+	recursive := c.Params.Submodules == submodulesModeRecursive
+
+	if err := c.checkoutSubmodules(repoDir, "", c.Params.RepoUrl, c.Params.SubmodulePaths, recursive); err != nil {
+		return nil, err
+	}
+
+	return c.CliWrappers.GitCli.ListSubmodules(repoDir, recursive)
+}
+
+// checkoutSubmodules checks urls of the declared submodules against the url policy and then checks them out,
+// so denied repositories are never fetched.
+// Nested submodules are declared inside of the checked out submodules only, so recursive checkout is done level by level.
+// submodulePath is the path of the processed repository relative to the main repository, empty for the main one.
+func (c *GitClone) checkoutSubmodules(repoDir, submodulePath, repoUrl string, paths []string, recursive bool) error {
+	moduleDir := filepath.Join(repoDir, submodulePath)
+
+	declaredSubmodules, err := c.CliWrappers.GitCli.ListDeclaredSubmodules(moduleDir)
+	if err != nil {
+		return err
+	}
+	submoduleUrls := map[string]string{}
+	for _, submodule := range declaredSubmodules {
+		if !isSubmoduleSelected(submodule.Path, paths) {
+			continue
+		}
+		submoduleUrl := resolveSubmoduleUrl(repoUrl, submodule.Url)
+		if err := checkUrlPolicy(submoduleUrl, c.urlPolicy); err != nil {
+			return fmt.Errorf("submodule '%s' is not allowed: %w", path.Join(submodulePath, submodule.Path), err)
+		}
+		submoduleUrls[submodule.Path] = submoduleUrl
+	}
+	if len(submoduleUrls) == 0 {
+		return nil
+	}
+
+	submoduleUpdateArgs := &cliWrappers.GitSubmoduleUpdateArgs{
+		RepoDir: moduleDir,
+		Depth:   c.Params.SubmodulesDepth,
+		Paths:   paths,
+	}
+	if err := c.CliWrappers.GitCli.SubmoduleUpdate(submoduleUpdateArgs); err != nil {
+		return err
+	}
+
+	if !recursive {
+		return nil
+	}
+	for _, submodule := range declaredSubmodules {
+		submoduleUrl, isSelected := submoduleUrls[submodule.Path]
+		if !isSelected {
+			continue
+		}
+		if err := c.checkoutSubmodules(repoDir, path.Join(submodulePath, submodule.Path), submoduleUrl, nil, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isSubmoduleSelected checks if the submodule is checked out with the given paths filter.
+// All submodules are selected if the filter is empty.
+func isSubmoduleSelected(submodulePath string, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, p := range paths {
+		p = strings.TrimSuffix(path.Clean(p), "/")
+		if submodulePath == p || strings.HasPrefix(submodulePath, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveSubmoduleUrl resolves submodule url relative to the parent repository url, e.g. "../lib.git",
+// the same way as git does. Absolute urls are returned as is.
+func resolveSubmoduleUrl(parentUrl, submoduleUrl string) string {
+	if !strings.HasPrefix(submoduleUrl, "./") && !strings.HasPrefix(submoduleUrl, "../") {
+		return submoduleUrl
+	}
+
+	baseUrl := strings.TrimSuffix(parentUrl, "/")
+	for {
+		if strings.HasPrefix(submoduleUrl, "./") {
+			submoduleUrl = strings.TrimPrefix(submoduleUrl, "./")
+		} else if strings.HasPrefix(submoduleUrl, "../") {
+			submoduleUrl = strings.TrimPrefix(submoduleUrl, "../")
+			// Scp-like urls, e.g. git@host:org/repo.git, have colon before the first path segment
+			if lastSlash := strings.LastIndex(baseUrl, "/"); lastSlash != -1 && !strings.HasSuffix(baseUrl[:lastSlash], ":/") {
+				baseUrl = baseUrl[:lastSlash]
+			} else if lastColon := strings.LastIndex(baseUrl, ":"); lastColon != -1 {
+				return baseUrl[:lastColon+1] + submoduleUrl
+			}
+		} else {
+			break
+		}
+	}
+	return baseUrl + "/" + submoduleUrl
+}
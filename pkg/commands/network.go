@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+
+	cliWrappers "github.com/mmorhun/konflux-task-cli/pkg/cliwrappers"
+	"github.com/mmorhun/konflux-task-cli/pkg/common"
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+)
+
+// Network parameters are shared by commands that access remote servers.
+// The settings are applied to the executed tools only, the CLI process environment is not modified.
+var (
+	httpProxyParam = common.Parameter{
+		Name:       "http-proxy",
+		EnvVarName: "HTTP_PROXY_URL",
+		TypeKind:   reflect.String,
+		Usage:      "Proxy url for http connections",
+		// Proxy url may contain credentials
+		Sensitive: true,
+	}
+	httpsProxyParam = common.Parameter{
+		Name:       "https-proxy",
+		EnvVarName: "HTTPS_PROXY_URL",
+		TypeKind:   reflect.String,
+		Usage:      "Proxy url for https connections",
+		Sensitive:  true,
+	}
+	noProxyParam = common.Parameter{
+		Name:       "no-proxy",
+		EnvVarName: "NO_PROXY_HOSTS",
+		TypeKind:   reflect.String,
+		Usage:      "Comma separated list of hosts to connect to without proxy",
+	}
+	caBundleParam = common.Parameter{
+		Name:       "ca-bundle",
+		EnvVarName: "CA_BUNDLE_PATH",
+		TypeKind:   reflect.String,
+		Usage:      "Path to PEM file with all trusted certificate authorities, replaces the system ones",
+	}
+)
+
+// NetworkParams holds the network parameters, it's embedded into params of commands that access remote servers.
+type NetworkParams struct {
+	HttpProxy  string `paramName:"http-proxy"`
+	HttpsProxy string `paramName:"https-proxy"`
+	NoProxy    string `paramName:"no-proxy"`
+	CaBundle   string `paramName:"ca-bundle"`
+}
+
+// networkConfig returns proxy and CA bundle settings for the executed tools.
+func (p *NetworkParams) networkConfig() *cliWrappers.NetworkConfig {
+	return &cliWrappers.NetworkConfig{
+		HttpProxy:  p.HttpProxy,
+		HttpsProxy: p.HttpsProxy,
+		NoProxy:    p.NoProxy,
+		CaBundle:   p.CaBundle,
+	}
+}
+
+// validateNetworkConfig checks that proxy urls are valid and the CA bundle file exists.
+func validateNetworkConfig(networkConfig *cliWrappers.NetworkConfig) error {
+	for paramName, proxyUrl := range map[string]string{
+		httpProxyParam.Name:  networkConfig.HttpProxy,
+		httpsProxyParam.Name: networkConfig.HttpsProxy,
+	} {
+		if proxyUrl == "" {
+			continue
+		}
+		parsedUrl, err := url.Parse(proxyUrl)
+		if err != nil || parsedUrl.Host == "" {
+			return fmt.Errorf("%s parameter must be an url, e.g. http://proxy.example.com:3128", paramName)
+		}
+		switch parsedUrl.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("%s parameter has unsupported scheme '%s'", paramName, parsedUrl.Scheme)
+		}
+	}
+	if networkConfig.CaBundle != "" {
+		info, err := os.Stat(networkConfig.CaBundle)
+		if err != nil {
+			return fmt.Errorf("failed to access CA bundle: %w", err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("CA bundle '%s' is not a file", networkConfig.CaBundle)
+		}
+	}
+	return nil
+}
+
+// logNetworkConfig prints the network parameters in verbose mode.
+func logNetworkConfig(networkConfig *cliWrappers.NetworkConfig) {
+	if networkConfig.HttpProxy != "" {
+		l.Logger.Infof("[param] http proxy: %s", networkConfig.HttpProxy)
+	}
+	if networkConfig.HttpsProxy != "" {
+		l.Logger.Infof("[param] https proxy: %s", networkConfig.HttpsProxy)
+	}
+	if networkConfig.NoProxy != "" {
+		l.Logger.Infof("[param] no proxy: %s", networkConfig.NoProxy)
+	}
+	if networkConfig.CaBundle != "" {
+		l.Logger.Infof("[param] CA bundle: %s", networkConfig.CaBundle)
+	}
+}
+
+// newNetworkExecutor creates executor which applies the network settings to executed commands.
+func newNetworkExecutor(networkConfig *cliWrappers.NetworkConfig, verbose bool) cliWrappers.CliExecutorInterface {
+	executor := cliWrappers.NewCliExecutor(verbose)
+	if networkConfig.IsEmpty() {
+		return executor
+	}
+	return executor.WithEnv(networkConfig.Env())
+}
+
+// newNetworkGitBackend creates git implementation that honors the network settings.
+// go-git backend doesn't use the executor, so it cannot be used with the network settings.
+func newNetworkGitBackend(backend string, networkConfig *cliWrappers.NetworkConfig, verbose bool) (cliWrappers.GitCliInterface, error) {
+	gitCli, err := newGitBackend(backend, newNetworkExecutor(networkConfig, verbose), verbose)
+	if err != nil {
+		return nil, err
+	}
+	if _, isGoGit := gitCli.(*cliWrappers.GoGit); isGoGit && !networkConfig.IsEmpty() {
+		return nil, fmt.Errorf("proxy and CA bundle parameters are %w", cliWrappers.ErrGoGitUnsupported)
+	}
+	return gitCli, nil
+}
@@ -0,0 +1,24 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// RemoveDirContent deletes everything inside the given directory, but keeps the directory itself.
+// It allows to clean a mount point, which cannot be deleted.
+func RemoveDirContent(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
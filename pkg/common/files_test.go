@@ -0,0 +1,32 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRemoveDirContent(t *testing.T) {
+	t.Run("should delete directory content and keep the directory", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+		g.Expect(os.MkdirAll(filepath.Join(dir, "sub", ".git"), 0755)).To(Succeed())
+		g.Expect(os.WriteFile(filepath.Join(dir, ".hidden"), []byte("data"), 0644)).To(Succeed())
+		g.Expect(os.WriteFile(filepath.Join(dir, "sub", "file"), []byte("data"), 0644)).To(Succeed())
+
+		g.Expect(RemoveDirContent(dir)).To(Succeed())
+
+		entries, err := os.ReadDir(dir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(entries).To(BeEmpty())
+	})
+
+	t.Run("should ignore missing directory", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(RemoveDirContent(filepath.Join(t.TempDir(), "missing"))).To(Succeed())
+	})
+}
@@ -8,6 +8,8 @@ import (
 	"strconv"
 	"strings"
 
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+	"github.com/mmorhun/konflux-task-cli/pkg/redact"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +21,10 @@ type Parameter struct {
 	DefaultValue string // makes no sense if Required is true
 	Usage        string
 	Required     bool
+	// Sensitive marks parameter which value is a secret or may contain one, e.g. proxy url with password.
+	// The value is registered for redaction and masked in logs.
+	// Secrets read from files given by parameters, e.g. tokens, are registered where the files are read.
+	Sensitive bool
 }
 
 // RegisterParameters configures Cobra CLI parameters based on given Parameters data.
@@ -104,15 +110,15 @@ func ParseParameters(cmd *cobra.Command, paramsConfig map[string]Parameter, para
 	// Iterate over parameters in the top loop to avoid missing a required parameter
 	for tag, paramData := range paramsConfig {
 		fieldFound := false
-		for i := 0; i < paramsStruct.NumField(); i++ {
-			field := paramsStructType.Field(i)
+		// Visible fields include fields of embedded structs, e.g. a group of parameters shared by several commands
+		for _, field := range reflect.VisibleFields(paramsStructType) {
 			fieldTag := field.Tag.Get("paramName")
 			if fieldTag == "" {
 				// Skip if no paramName tag
 				continue
 			}
 			if fieldTag == tag {
-				fieldValue := paramsStruct.Field(i)
+				fieldValue := paramsStruct.FieldByIndex(field.Index)
 				if fieldValue.CanSet() {
 					paramProvided := cmd.Flags().Changed(paramData.Name)
 
@@ -236,6 +242,10 @@ func ParseParameters(cmd *cobra.Command, paramsConfig map[string]Parameter, para
 						panic(fmt.Sprintf("ParseParameters: not supported parameter type '%v' for '%s' parameter", fieldValue.Kind(), paramData.Name))
 					}
 
+					if paramData.Sensitive {
+						registerSensitiveValue(fieldValue, paramData.Name)
+					}
+
 					fieldFound = true
 					break
 				} else {
@@ -249,3 +259,22 @@ func ParseParameters(cmd *cobra.Command, paramsConfig map[string]Parameter, para
 	}
 	return nil
 }
+
+// registerSensitiveValue registers string or string array parameter value as a secret.
+// Warns if a value is too short to be masked.
+func registerSensitiveValue(fieldValue reflect.Value, paramName string) {
+	var values []string
+	switch fieldValue.Kind() {
+	case reflect.String:
+		values = append(values, fieldValue.String())
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < fieldValue.Len(); i++ {
+			values = append(values, fieldValue.Index(i).String())
+		}
+	}
+	for _, value := range values {
+		if err := redact.AddSecret(value); err != nil {
+			l.Logger.Warnf("Value of '%s' parameter is not masked in logs: %v", paramName, err)
+		}
+	}
+}
@@ -1,10 +1,13 @@
 package common
 
 import (
+	"bytes"
 	"os"
 	"reflect"
 	"testing"
 
+	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+	"github.com/mmorhun/konflux-task-cli/pkg/redact"
 	. "github.com/onsi/gomega"
 	"github.com/spf13/cobra"
 )
@@ -689,4 +692,115 @@ func TestParseParameters(t *testing.T) {
 			ParseParameters(cmd, paramsConfig, params)
 		}).To(Panic())
 	})
+
+	t.Run("should parse parameters of embedded struct", func(t *testing.T) {
+		g := NewWithT(t)
+
+		type SharedParams struct {
+			SharedParam string `paramName:"sharedParam"`
+		}
+		type EmbeddingParams struct {
+			StringParam string `paramName:"stringParam"`
+			SharedParams
+		}
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("stringParam", "", "usage")
+		cmd.Flags().String("sharedParam", "", "usage")
+		cmd.Flags().Set("stringParam", "test-value")
+		cmd.Flags().Set("sharedParam", "shared-value")
+
+		paramsConfig := map[string]Parameter{
+			"stringParam": {
+				Name:     "stringParam",
+				TypeKind: reflect.String,
+			},
+			"sharedParam": {
+				Name:     "sharedParam",
+				TypeKind: reflect.String,
+			},
+		}
+
+		params := &EmbeddingParams{}
+		err := ParseParameters(cmd, paramsConfig, params)
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(params.StringParam).To(Equal("test-value"))
+		g.Expect(params.SharedParam).To(Equal("shared-value"))
+	})
+
+	t.Run("should register sensitive parameter values as secrets", func(t *testing.T) {
+		g := NewWithT(t)
+		defer redact.ResetSecrets()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("stringParam", "", "usage")
+		cmd.Flags().StringArray("arrayParam", nil, "usage")
+		cmd.Flags().String("plainParam", "", "usage")
+		cmd.Flags().Set("stringParam", "secret-string")
+		cmd.Flags().Set("arrayParam", "secret-item1")
+		cmd.Flags().Set("arrayParam", "secret-item2")
+		cmd.Flags().Set("plainParam", "plain-value")
+
+		type SensitiveParams struct {
+			StringParam string   `paramName:"stringParam"`
+			ArrayParam  []string `paramName:"arrayParam"`
+			PlainParam  string   `paramName:"plainParam"`
+		}
+		paramsConfig := map[string]Parameter{
+			"stringParam": {
+				Name:      "stringParam",
+				TypeKind:  reflect.String,
+				Sensitive: true,
+			},
+			"arrayParam": {
+				Name:      "arrayParam",
+				TypeKind:  reflect.Array,
+				Sensitive: true,
+			},
+			"plainParam": {
+				Name:     "plainParam",
+				TypeKind: reflect.String,
+			},
+		}
+
+		params := &SensitiveParams{}
+		err := ParseParameters(cmd, paramsConfig, params)
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(params.StringParam).To(Equal("secret-string"))
+		g.Expect(params.ArrayParam).To(Equal([]string{"secret-item1", "secret-item2"}))
+		g.Expect(redact.String("secret-string secret-item1 secret-item2 plain-value")).To(Equal("xxxxx xxxxx xxxxx plain-value"))
+	})
+
+	t.Run("should warn about sensitive parameter value too short to be masked", func(t *testing.T) {
+		g := NewWithT(t)
+		defer redact.ResetSecrets()
+
+		var output bytes.Buffer
+		l.Logger.SetOutput(&output)
+		defer l.Logger.SetOutput(os.Stderr)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("stringParam", "", "usage")
+		cmd.Flags().Set("stringParam", "pa$$")
+
+		type SensitiveParams struct {
+			StringParam string `paramName:"stringParam"`
+		}
+		paramsConfig := map[string]Parameter{
+			"stringParam": {
+				Name:      "stringParam",
+				TypeKind:  reflect.String,
+				Sensitive: true,
+			},
+		}
+
+		params := &SensitiveParams{}
+		err := ParseParameters(cmd, paramsConfig, params)
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(output.String()).To(ContainSubstring("Value of 'stringParam' parameter is not masked in logs"))
+		g.Expect(output.String()).ToNot(ContainSubstring("pa$$"))
+	})
 }
@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 
 	l "github.com/mmorhun/konflux-task-cli/pkg/logger"
+	"github.com/mmorhun/konflux-task-cli/pkg/redact"
 )
 
 // ReadResultFilesPath fills the given results path struct with file path defined in env vars.
 // Each field of the result path struct must be of string type and have 'env' tag.
+// A result can be marked optional via the 'optional' tag option, e.g. `env:"RESULT_NAME,optional"`.
+// If the environment variable of an optional result is not set, the field is left empty.
 func ReadResultFilesPath(resultFilesPath interface{}) error {
 	resultsStruct := reflect.ValueOf(resultFilesPath).Elem()
 	paramsStructType := resultsStruct.Type()
@@ -22,13 +26,17 @@ func ReadResultFilesPath(resultFilesPath interface{}) error {
 			panic(fmt.Sprintf("ReadResultFilesPath: result '%s' path is not of string type", field.Name))
 		}
 
-		envVarName := field.Tag.Get("env")
+		envVarName, tagOptions, _ := strings.Cut(field.Tag.Get("env"), ",")
 		if envVarName == "" {
 			panic(fmt.Sprintf("ReadResultFilesPath: result '%s' path 'env' tag is not set", field.Name))
 		}
+		isOptional := tagOptions == "optional"
 
 		envValue := os.Getenv(envVarName)
 		if envValue == "" {
+			if isOptional {
+				continue
+			}
 			return fmt.Errorf("ReadResultFilesPath: environment variable '%s' for '%s' result is not set", envVarName, field.Name)
 		}
 
@@ -56,8 +64,10 @@ func NewResultsWriter(verbose bool) *ResultsWriter {
 	return &ResultsWriter{Verbose: verbose}
 }
 
-// WriteResultString writes result data into file by given path
+// WriteResultString writes result data into file by given path.
+// Credentials in urls are masked in the written data, other data is written as is.
 func (r *ResultsWriter) WriteResultString(result, path string) error {
+	result = redact.UrlCredentials(result)
 	if err := os.WriteFile(path, []byte(result), 0644); err != nil {
 		return fmt.Errorf("failed to write into result file '%s': %w", path, err)
 	}
@@ -68,3 +78,12 @@ func (r *ResultsWriter) WriteResultString(result, path string) error {
 
 	return nil
 }
+
+// WriteOptionalResultString writes the result only if its file path is set.
+// File path of an optional result is empty if the result is not requested, see ReadResultFilesPath.
+func WriteOptionalResultString(resultsWriter ResultsWriterInterface, result, path string) error {
+	if path == "" {
+		return nil
+	}
+	return resultsWriter.WriteResultString(result, path)
+}
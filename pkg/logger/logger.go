@@ -1,12 +1,20 @@
 package logger
 
 import (
+	"fmt"
 	"os"
 
+	"github.com/mmorhun/konflux-task-cli/pkg/redact"
 	"github.com/sirupsen/logrus"
 )
 
-var Logger = logrus.New()
+var Logger = newLogger()
+
+func newLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetFormatter(&redactingFormatter{Formatter: logger.Formatter})
+	return logger
+}
 
 func InitLogger(logLevel string) error {
 	Logger.SetOutput(os.Stdout)
@@ -19,3 +27,25 @@ func InitLogger(logLevel string) error {
 
 	return nil
 }
+
+// redactingFormatter masks secrets in log messages and fields before formatting them.
+type redactingFormatter struct {
+	Formatter logrus.Formatter
+}
+
+func (f *redactingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	redactedEntry := entry.Dup()
+	redactedEntry.Level = entry.Level
+	redactedEntry.Caller = entry.Caller
+	redactedEntry.Buffer = entry.Buffer
+	redactedEntry.Message = redact.String(entry.Message)
+	for key, value := range redactedEntry.Data {
+		switch v := value.(type) {
+		case string:
+			redactedEntry.Data[key] = redact.String(v)
+		case error, fmt.Stringer:
+			redactedEntry.Data[key] = redact.String(fmt.Sprint(v))
+		}
+	}
+	return f.Formatter.Format(redactedEntry)
+}